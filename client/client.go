@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"tp1/pkg/inputs"
+	"tp1/pkg/transport"
+
+	pb "tp1/protocol/messages"
+
+	"google.golang.org/grpc"
+)
+
+const usage = `Uso:
+  go run client/client.go [-addr direccion] [-tls-cert cert -tls-key key -tls-ca ca] submit [-plugin plugin.so] [-output-prefix prefijo] [-total-order] [-intermediate-format formato] [-intermediate-codec codec] [-include patron] [-exclude patron] cant_reducers entradas...
+  go run client/client.go [-addr direccion] [-tls-cert cert -tls-key key -tls-ca ca] status job_id`
+
+func submitJob(client pb.ServerClient, args []string) {
+	flags := flag.NewFlagSet("submit", flag.ExitOnError)
+	pluginName := flags.String("plugin", "", "plugin con las funciones Map y Reduce del job")
+	outputPrefix := flags.String("output-prefix", "", "prefijo de los archivos de salida (por defecto output/<job_id>/mr-out)")
+	totalOrder := flags.Bool("total-order", false, "particionar por rangos para que la salida quede ordenada globalmente")
+	intermediateFormat := flags.String("intermediate-format", "", "formato de los archivos intermedios: text, jsonl o binary (por defecto el del coordinator)")
+	intermediateCodec := flags.String("intermediate-codec", "", "compresión de los archivos intermedios: none, gzip, zlib o flate (por defecto la del coordinator)")
+	inputFilters := inputs.RegisterFilterFlags(flags)
+	flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		log.Fatal(usage)
+	}
+
+	reducersAmount, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := client.SubmitJob(context.Background(), &pb.JobSubmission{
+		InputFiles:         flags.Args()[1:],
+		Include:            inputFilters.Include,
+		Exclude:            inputFilters.Exclude,
+		ReducerNumber:      int32(reducersAmount),
+		Plugin:             *pluginName,
+		OutputPrefix:       *outputPrefix,
+		TotalOrder:         *totalOrder,
+		IntermediateFormat: *intermediateFormat,
+		IntermediateCodec:  *intermediateCodec,
+	})
+	if err != nil {
+		log.Fatalf("Error enviando el job: %v", err)
+	}
+
+	fmt.Printf("%s (%s)\n", resp.JobId, resp.Response)
+}
+
+func printJobStatus(client pb.ServerClient, args []string) {
+	if len(args) != 1 {
+		log.Fatal(usage)
+	}
+
+	resp, err := client.GetJobStatus(context.Background(), &pb.JobStatusRequest{JobId: args[0]})
+	if err != nil {
+		log.Fatalf("Error consultando el job: %v", err)
+	}
+
+	fmt.Printf("%s: %s\n", resp.JobId, resp.JobStatus)
+	if resp.FailureReason != "" {
+		fmt.Printf("  Motivo del fallo: %s\n", resp.FailureReason)
+	}
+	fmt.Printf("  Maps pendientes: %d\n", resp.MapsToDo)
+	fmt.Printf("  Reduces pendientes: %d\n", resp.ReducesToDo)
+	fmt.Printf("  Tareas en progreso: %d\n", resp.TasksInProgress)
+	fmt.Printf("  Tareas reasignadas: %d\n", resp.ReclaimedTasks)
+}
+
+func main() {
+	address := flag.String("addr", transport.DefaultAddress(), "dirección del coordinator: unix:///ruta o host:puerto (también se toma de "+transport.AddressEnv+")")
+	tlsConfig := transport.RegisterTLSClientFlags(flag.CommandLine)
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		log.Fatal(usage)
+	}
+
+	dialOption, err := tlsConfig.DialOption()
+	if err != nil {
+		log.Fatalf("Error en la configuración TLS: %v", err)
+	}
+
+	conn, err := grpc.Dial(transport.DialTarget(*address), dialOption)
+	if err != nil {
+		log.Fatalf("Error conectando al coordinator: %v", err)
+	}
+	defer conn.Close()
+
+	client := pb.NewServerClient(conn)
+
+	switch flag.Arg(0) {
+	case "submit":
+		submitJob(client, flag.Args()[1:])
+	case "status":
+		printJobStatus(client, flag.Args()[1:])
+	default:
+		log.Fatal(usage)
+	}
+}
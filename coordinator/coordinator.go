@@ -1,22 +1,97 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"math"
 	"os"
 	"strconv"
+	"time"
 	"tp1/coordinator/internal/communications"
+	"tp1/coordinator/internal/utils"
+	"tp1/pkg/inputs"
+	"tp1/pkg/intermediate"
+	"tp1/pkg/transport"
 )
 
 func main() {
 
-	reducersAmount, err := strconv.Atoi(os.Args[1])
+	address := flag.String("addr", transport.DefaultAddress(), "dirección donde escuchar: unix:///ruta o host:puerto (también se toma de "+transport.AddressEnv+")")
+	leaseDuration := flag.Duration("lease", 10*time.Second, "tiempo sin heartbeats tras el cual una tarea asignada se reasigna")
+	reapInterval := flag.Duration("reap-interval", time.Second, "cada cuánto se buscan tareas asignadas con el lease vencido")
+	stateLogName := flag.String("state-log", "coordinator.wal", "archivo, dentro del directorio intermedio de cada job, donde se persiste el estado de las tareas (vacío para deshabilitarlo)")
+	serve := flag.Bool("serve", false, "seguir aceptando jobs (SubmitJob) después de completar los actuales")
+	outputPrefix := flag.String("output-prefix", "output/mr-out", "prefijo de los archivos de salida del job pasado por línea de comandos")
+	pluginName := flag.String("plugin", "", "aplicación (wc, inverted_index, ...) del job pasado por línea de comandos; vacío para usar la de cada worker")
+	totalOrder := flag.Bool("total-order", false, "particionar por rangos de claves muestreadas para que mr-out-1..N concatenados queden ordenados globalmente")
+	intermediateFormat := flag.String("intermediate-format", intermediate.DefaultFormat, "formato de los archivos intermedios del job pasado por línea de comandos: text, jsonl o binary")
+	intermediateCodec := flag.String("intermediate-codec", intermediate.DefaultCodec, "compresión de los archivos intermedios del job pasado por línea de comandos: none, gzip, zlib o flate")
+	splitSize := flag.Int64("split-size", 64<<20, "tamaño máximo en bytes de la entrada de cada map: los archivos más grandes se cortan y los más chicos se agrupan (0 para un map por archivo)")
+	maxFailures := flag.Int("max-failures", 3, "cantidad de fallos reportados de una misma tarea tras la cual se la pone en cuarentena")
+	maxAttempts := flag.Uint("max-attempts", 10, "cantidad de intentos (asignaciones) de una misma tarea tras la cual se la pone en cuarentena")
+	speculationThreshold := flag.Int("speculation-threshold", 1, "cantidad de tareas pendientes de una fase por debajo de la cual se lanzan copias de respaldo de las más lentas (0 deshabilita)")
+	speculationDelay := flag.Duration("speculation-delay", 5*time.Second, "tiempo mínimo que una tarea tiene que llevar en curso para lanzarle una copia de respaldo")
+	skipPoisonTasks := flag.Bool("skip-poison-tasks", false, "en vez de fallar el job, omitir las tareas en cuarentena y terminar con resultados parciales")
+	inputFilters := inputs.RegisterFilterFlags(flag.CommandLine)
+	tlsConfig := transport.RegisterTLSFlags(flag.CommandLine)
+	flag.Parse()
 
-	if err != nil {
+	if flag.NArg() == 1 {
+		log.Fatal("Uso: go run coordinator.go [-serve] [-addr direccion] [-tls-cert cert -tls-key key -tls-ca ca] [-lease 10s] [-reap-interval 1s] [-state-log archivo] [-max-failures 3] [-max-attempts 10] [-skip-poison-tasks] [-speculation-threshold 1] [-speculation-delay 5s] [-output-prefix prefijo] [-plugin aplicacion] [-total-order] [-intermediate-format jsonl] [-intermediate-codec none] [-split-size bytes] [-include patron] [-exclude patron] [cant_reducers entradas...]")
+	}
+
+	if *maxAttempts > math.MaxUint32 {
+		log.Fatalf("the max attempts can be at most %d, got %d", uint32(math.MaxUint32), *maxAttempts)
+	}
+
+	config := communications.Config{
+		Address: *address,
+		TLS:     tlsConfig,
+		Scheduling: utils.SchedulingConfig{
+			LeaseDuration:        *leaseDuration,
+			MaxAttempts:          uint32(*maxAttempts),
+			MaxFailures:          *maxFailures,
+			SkipPoisonTasks:      *skipPoisonTasks,
+			SpeculationThreshold: *speculationThreshold,
+			SpeculationDelay:     *speculationDelay,
+		},
+		SplitSize:    *splitSize,
+		ReapInterval: *reapInterval,
+		StateLogName: *stateLogName,
+		KeepServing:  *serve || flag.NArg() == 0,
+	}
+	if err := config.Validate(); err != nil {
 		log.Fatal(err)
 	}
+	coordinator := communications.NewCoordinator(config)
+
+	if flag.NArg() >= 2 {
+		reducersAmount, err := strconv.Atoi(flag.Arg(0))
 
-	fileSplits := os.Args[2:]
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fileSplits := flag.Args()[1:]
+
+		_, _, err = coordinator.SubmitJob(communications.JobSpec{
+			InputFiles:         fileSplits,
+			InputFilters:       *inputFilters,
+			ReducerAmount:      reducersAmount,
+			Plugin:             *pluginName,
+			OutputPrefix:       *outputPrefix,
+			TotalOrder:         *totalOrder,
+			IntermediateFormat: *intermediateFormat,
+			IntermediateCodec:  *intermediateCodec,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	coordinator := communications.NewCoordinator(fileSplits, uint8(reducersAmount))
 	coordinator.StartCoordinator()
+
+	if coordinator.HasFailedJobs() {
+		os.Exit(1)
+	}
 }
@@ -1,52 +1,203 @@
 package communications
 
 import (
+	"fmt"
 	"google.golang.org/grpc"
 	"log"
-	"net"
-	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
 	"tp1/coordinator/internal/utils"
+	"tp1/coordinator/internal/wal"
+	"tp1/pkg/inputs"
+	"tp1/pkg/intermediate"
+	"tp1/pkg/transport"
 	pb "tp1/protocol/messages"
 )
 
+type Config struct {
+	Address      string
+	TLS          *transport.TLSConfig
+	Scheduling   utils.SchedulingConfig
+	SplitSize    int64
+	ReapInterval time.Duration
+	StateLogName string
+	KeepServing  bool
+}
+
+func (config Config) Validate() error {
+	if err := config.Scheduling.Validate(); err != nil {
+		return err
+	}
+	if config.ReapInterval <= 0 {
+		return fmt.Errorf("the reap interval must be positive, got %v", config.ReapInterval)
+	}
+	if config.SplitSize < 0 {
+		return fmt.Errorf("the split size can't be negative, got %d", config.SplitSize)
+	}
+	return nil
+}
+
+type JobSpec struct {
+	// InputFiles puede incluir directorios, patrones y manifiestos (@archivo), que se resuelven con inputs.Resolve
+	InputFiles         []string
+	InputFilters       inputs.Filters
+	ReducerAmount      int
+	Plugin             string
+	OutputPrefix       string
+	TotalOrder         bool
+	IntermediateFormat string
+	IntermediateCodec  string
+}
+
 type Coordinator struct {
 	communicationHandler *communicationHandler
-	sharedResources      *utils.SharedResources
-	mappersAmount        uint8
-	reducersAmount       uint8
+	jobs                 *utils.JobTable
+	submitMutex          sync.Mutex
+	config               Config
 	shutdownChan         chan bool
 }
 
-func NewCoordinator(fileSplits []string, reducersAmount uint8) *Coordinator {
+func NewCoordinator(config Config) *Coordinator {
 
-	sharedResources := utils.CreateInitialSharedResources(fileSplits, reducersAmount)
+	jobs := utils.CreateJobTable()
 	shutdownChan := make(chan bool, 1)
 
-	return &Coordinator{
-		communicationHandler: &communicationHandler{sharedResources: sharedResources, shutdownChan: shutdownChan},
-		sharedResources:      sharedResources,
-		mappersAmount:        uint8(len(fileSplits)),
-		reducersAmount:       reducersAmount,
-		shutdownChan:         shutdownChan,
+	coordinator := &Coordinator{
+		jobs:         jobs,
+		config:       config,
+		shutdownChan: shutdownChan,
 	}
+	coordinator.communicationHandler = &communicationHandler{coordinator: coordinator, jobs: jobs}
+
+	return coordinator
 }
 
-func (c *Coordinator) StartCoordinator() {
-	socketPath := "/tmp/mr-socket.sock"
+func (c *Coordinator) SubmitJob(spec JobSpec) (*utils.Job, bool, error) {
+	c.submitMutex.Lock()
+	defer c.submitMutex.Unlock()
+
+	inputFiles, err := inputs.Resolve(spec.InputFiles, spec.InputFilters)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(inputFiles) == 0 {
+		return nil, false, fmt.Errorf("a job needs at least one input file")
+	}
+	spec.InputFiles = inputFiles
+
+	if spec.ReducerAmount <= 0 || spec.ReducerAmount > utils.MaxReducers {
+		return nil, false, fmt.Errorf("a job needs between 1 and %d reducers, got %d", utils.MaxReducers, spec.ReducerAmount)
+	}
+	if spec.IntermediateFormat == "" {
+		spec.IntermediateFormat = intermediate.DefaultFormat
+	}
+	if spec.IntermediateCodec == "" {
+		spec.IntermediateCodec = intermediate.DefaultCodec
+	}
+	encoding := intermediate.Encoding{Format: spec.IntermediateFormat, Codec: spec.IntermediateCodec}
+	if err := encoding.Validate(); err != nil {
+		return nil, false, err
+	}
+	// Los workers cargan el plugin desde su directorio de plugins: una ruta permitiría hacerles abrir cualquier .so
+	if spec.Plugin != "" && (filepath.Base(spec.Plugin) != spec.Plugin || spec.Plugin == "." || spec.Plugin == "..") {
+		return nil, false, fmt.Errorf("the plugin must be a name inside the workers' plugins directory, got %q", spec.Plugin)
+	}
 
-	os.Remove(socketPath)
+	jobArguments := []string{strconv.Itoa(int(spec.ReducerAmount)), spec.Plugin, spec.OutputPrefix}
+	if spec.TotalOrder {
+		jobArguments = append(jobArguments, "total-order")
+	}
+	jobArguments = append(jobArguments, "split-size="+strconv.FormatInt(c.config.SplitSize, 10))
+	jobArguments = append(jobArguments, spec.InputFiles...)
+	fingerprint := wal.Fingerprint(jobArguments...)
+	jobId := "job-" + fingerprint[:12]
 
-	lis, err := net.Listen("unix", socketPath)
+	// Solo se deduplica un job en curso: uno terminado o fallido se reemplaza por una ejecución nueva, con otra
+	// generación para que los workers no reutilicen la configuración que guardaron de la anterior
+	generation := 1
+	if job := c.jobs.GetJob(jobId); job != nil {
+		if c.jobs.IsJobRunning(jobId) {
+			return job, true, nil
+		}
+		generation = job.Generation + 1
+	}
+
+	if spec.OutputPrefix == "" {
+		spec.OutputPrefix = filepath.Join("output", jobId, "mr-out")
+	}
+
+	stateLogPath := ""
+	if c.config.StateLogName != "" {
+		stateLogPath = filepath.Join(utils.IntermediateDir(jobId), c.config.StateLogName)
+	}
+
+	stateLog, pendingEntries, err := wal.Open(stateLogPath, fingerprint)
+	if err != nil {
+		return nil, false, fmt.Errorf("state log error: %v", err)
+	}
+
+	mapInputs := utils.SplitInputs(spec.InputFiles, c.config.SplitSize)
+	if len(mapInputs) > utils.MaxMapTasks {
+		return nil, false, fmt.Errorf("the input files need %d map tasks but a job supports at most %d, use a bigger split size",
+			len(mapInputs), utils.MaxMapTasks)
+	}
+
+	sharedResources := utils.CreateInitialSharedResources(mapInputs, spec.ReducerAmount, spec.TotalOrder, c.config.Scheduling, stateLog)
+
+	if len(pendingEntries) > 0 {
+		sharedResources.Restore(pendingEntries)
+		progress := sharedResources.GetProgress()
+		log.Printf("Recovered state of job %s from %s: %d maps and %d reduces left",
+			jobId, stateLogPath, progress.MapsToDo, progress.ReducesToDo)
+	}
+
+	job := &utils.Job{
+		JobId:              jobId,
+		Generation:         generation,
+		InputFiles:         spec.InputFiles,
+		ReducerAmount:      spec.ReducerAmount,
+		MapAmount:          len(mapInputs),
+		Plugin:             spec.Plugin,
+		OutputPrefix:       spec.OutputPrefix,
+		TotalOrder:         spec.TotalOrder,
+		IntermediateFormat: spec.IntermediateFormat,
+		IntermediateCodec:  spec.IntermediateCodec,
+		JobStatus:          utils.JobRunning,
+		SharedResources:    sharedResources,
+		StateLog:           stateLog,
+	}
+	c.jobs.AddJob(job)
+
+	log.Printf("Job %s submitted: %d input files in %d map tasks, %d reducers, %s intermediate files (codec %s)", jobId,
+		len(spec.InputFiles), len(mapInputs), spec.ReducerAmount, spec.IntermediateFormat, spec.IntermediateCodec)
+
+	c.handlePoisonTasks(job)
+	if sharedResources.IsAllWorkCompleted() {
+		c.completeJob(job)
+	}
+
+	return job, false, nil
+}
+
+func (c *Coordinator) StartCoordinator() {
+	lis, err := transport.Listen(c.config.Address)
 	if err != nil {
 		log.Fatalf("Socket listening error: %v", err)
 	}
 	defer lis.Close()
 
-	grpcServer := grpc.NewServer()
+	serverOptions, err := c.config.TLS.ServerOptions()
+	if err != nil {
+		log.Fatalf("TLS configuration error: %v", err)
+	}
+
+	grpcServer := grpc.NewServer(serverOptions...)
 
 	pb.RegisterServerServer(grpcServer, c.communicationHandler)
 
-	log.Printf("Coordinator listening...")
+	log.Printf("Coordinator listening on %s...", c.config.Address)
 
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
@@ -54,9 +205,121 @@ func (c *Coordinator) StartCoordinator() {
 		}
 	}()
 
+	stopReaper := c.startReaper()
+
 	<-c.shutdownChan
-	log.Printf("All work completed. Shutting down...")
+	log.Printf("All jobs finished. Shutting down...")
+
+	stopReaper()
 
 	grpcServer.GracefulStop()
-	os.Remove(socketPath)
+	transport.Cleanup(c.config.Address)
+}
+
+func (c *Coordinator) completeJob(job *utils.Job) {
+	if !c.jobs.MarkJobAsCompleted(job.JobId) {
+		return
+	}
+
+	if quarantined := job.SharedResources.GetQuarantinedTasks(); len(quarantined) > 0 {
+		manifestPath, err := writePartialResultsManifest(job, quarantined)
+		if err != nil {
+			log.Printf("Could not write partial results manifest of job %s: %v", job.JobId, err)
+		}
+		log.Printf("Job %s completed with partial results, %d tasks skipped (see %s)", job.JobId, len(quarantined), manifestPath)
+	} else {
+		log.Printf("Job %s completed", job.JobId)
+	}
+
+	c.finishJob(job)
+}
+
+func (c *Coordinator) handlePoisonTasks(job *utils.Job) {
+	quarantined := job.SharedResources.GetQuarantinedTasks()
+	if len(quarantined) == 0 {
+		return
+	}
+
+	if !c.config.Scheduling.SkipPoisonTasks {
+		c.failJob(job, poisonTasksReport(quarantined))
+		return
+	}
+
+	log.Printf("Job %s is skipping %d poison tasks", job.JobId, len(quarantined))
+	if job.SharedResources.IsAllWorkCompleted() {
+		c.completeJob(job)
+	}
+}
+
+func (c *Coordinator) failJob(job *utils.Job, failureReason string) {
+	if !c.jobs.MarkJobAsFailed(job.JobId, failureReason) {
+		return
+	}
+
+	log.Printf("Job %s failed: %s", job.JobId, failureReason)
+	c.finishJob(job)
+}
+
+func (c *Coordinator) finishJob(job *utils.Job) {
+	if err := job.StateLog.Remove(); err != nil {
+		log.Printf("Could not remove state log of job %s: %v", job.JobId, err)
+	}
+
+	if !c.config.KeepServing && c.jobs.AreAllJobsDone() {
+		select {
+		case c.shutdownChan <- true:
+		default:
+		}
+	}
+}
+
+func (c *Coordinator) HasFailedJobs() bool {
+	return c.jobs.HasFailedJobs()
+}
+
+func (c *Coordinator) startReaper() func() {
+	stop := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(c.config.ReapInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				for _, job := range c.jobs.GetRunningJobs() {
+					c.reapExpiredTasks(job)
+				}
+			}
+		}
+	}()
+
+	return func() { close(stop) }
+}
+
+func (c *Coordinator) reapExpiredTasks(job *utils.Job) {
+	reclaimed := job.SharedResources.ReclaimExpiredTasks()
+	if len(reclaimed) == 0 {
+		return
+	}
+
+	anyQuarantined := false
+	for _, task := range reclaimed {
+		log.Printf("A worker died! %s task %s of job %s reclaimed from Worker<%s>", task.TaskType, task.WorkName, job.JobId, task.LostBy)
+		if task.Quarantined {
+			log.Printf("%s task %s of job %s quarantined after too many attempts", task.TaskType, task.WorkName, job.JobId)
+			anyQuarantined = true
+		}
+	}
+
+	progress := job.SharedResources.GetProgress()
+	log.Printf("Progress of job %s: %d maps and %d reduces left, %d tasks in progress, %d tasks reclaimed so far, %d quarantined, %d backup attempts",
+		job.JobId, progress.MapsToDo, progress.ReducesToDo, progress.TasksInProgress, progress.ReclaimedTasks, progress.QuarantinedTasks,
+		progress.SpeculativeTasks)
+
+	if anyQuarantined {
+		c.handlePoisonTasks(job)
+	}
 }
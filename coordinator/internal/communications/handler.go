@@ -4,26 +4,43 @@ import (
 	"context"
 	"log"
 	"tp1/coordinator/internal/utils"
+	"tp1/pkg/inputs"
+	"tp1/pkg/transport"
 	pb "tp1/protocol/messages"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type communicationHandler struct {
 	pb.UnimplementedServerServer
-	sharedResources *utils.SharedResources
-	shutdownChan    chan bool
+	coordinator *Coordinator
+	jobs        *utils.JobTable
 }
 
 func (c *communicationHandler) AskForWork(ctx context.Context, req *pb.ImFree) (*pb.AskForWorkResponse, error) {
 	log.Printf("Someone asked for work")
 
-	workToDo := c.sharedResources.GetAndAssignAvailableWork(req.WorkerUuid)
+	workerIdentity := transport.PeerIdentity(ctx)
+	job, workToDo := c.jobs.GetAndAssignAvailableWork(req.WorkerUuid, workerIdentity)
 
 	if workToDo != nil {
 		log.Printf("Worker<%s> wants job", req.WorkerUuid)
-		resp := utils.BuildAskForWorkResponse(workToDo.WorkName, int32(workToDo.Task.TaskId), workToDo.Task.TaskType, workToDo.ReducerAmount)
-		log.Printf("Assigned job to Worker<%s>", req.WorkerUuid)
+		resp := utils.BuildAskForWorkResponse(job, workToDo)
+		if workToDo.Speculative {
+			log.Printf("Launching backup attempt %d of straggler %s task %s", workToDo.Attempt, workToDo.Task.TaskType, workToDo.WorkName)
+		}
+		if workerIdentity != "" {
+			log.Printf("Assigned job %s to Worker<%s> authenticated as %s", job.JobId, req.WorkerUuid, workerIdentity)
+		} else {
+			log.Printf("Assigned job %s to Worker<%s>", job.JobId, req.WorkerUuid)
+		}
 		return resp, nil
 
+	} else if c.coordinator.config.KeepServing || !c.jobs.AreAllJobsDone() {
+		log.Printf("There's no work avalaible yet")
+		return &pb.AskForWorkResponse{WorkType: "Wait"}, nil
+
 	} else {
 		log.Printf("There's no work avalaible")
 		return &pb.AskForWorkResponse{WorkType: "Work finished"}, nil
@@ -32,14 +49,135 @@ func (c *communicationHandler) AskForWork(ctx context.Context, req *pb.ImFree) (
 
 func (c *communicationHandler) MarkWorkAsFinished(ctx context.Context, req *pb.IFinished) (*pb.IFinishedResponse, error) {
 	log.Printf("A worker finished a job")
-	c.sharedResources.MarkWorkAsFinished(req.WorkFinished, req.WorkType)
 
-	if c.sharedResources.IsAllWorkCompleted() {
-		select {
-		case c.shutdownChan <- true:
-		default:
-		}
+	job := c.jobs.GetJob(req.JobId)
+	if job == nil {
+		log.Printf("Worker<%s> finished work of unknown job %s", req.WorkerUuid, req.JobId)
+		return &pb.IFinishedResponse{Response: utils.ReportUnknownJob}, nil
+	}
+
+	result := job.SharedResources.MarkWorkAsFinished(req.WorkFinished, req.WorkType, req.WorkerUuid,
+		transport.PeerIdentity(ctx), uint32(req.Attempt), req.SampleKeys, req.ShuffleAddress)
+	if result != utils.ReportAccepted {
+		log.Printf("Ignoring report of %s (attempt %d) from Worker<%s>: %s", req.WorkFinished, req.Attempt, req.WorkerUuid, result)
+		return &pb.IFinishedResponse{Response: result, Accepted: false}, nil
+	}
+
+	if job.SharedResources.IsAllWorkCompleted() {
+		c.coordinator.completeJob(job)
+	}
+
+	return &pb.IFinishedResponse{Response: result, Accepted: true}, nil
+}
+
+func (c *communicationHandler) Heartbeat(ctx context.Context, req *pb.ImAlive) (*pb.ImAliveResponse, error) {
+	job := c.jobs.GetJob(req.JobId)
+
+	if job == nil || !job.SharedResources.RenewLease(req.WorkInProgress, req.WorkerUuid, transport.PeerIdentity(ctx),
+		uint32(req.Attempt), req.Committing) {
+		log.Printf("Worker<%s> no longer holds the lease of %s", req.WorkerUuid, req.WorkInProgress)
+		return &pb.ImAliveResponse{Response: "Lease lost"}, nil
+	}
+
+	return &pb.ImAliveResponse{Response: "OK"}, nil
+}
+
+func (c *communicationHandler) SubmitJob(ctx context.Context, req *pb.JobSubmission) (*pb.JobSubmissionResponse, error) {
+	job, alreadySubmitted, err := c.coordinator.SubmitJob(JobSpec{
+		InputFiles:         req.InputFiles,
+		InputFilters:       inputs.Filters{Include: req.Include, Exclude: req.Exclude},
+		ReducerAmount:      int(req.ReducerNumber),
+		Plugin:             req.Plugin,
+		OutputPrefix:       req.OutputPrefix,
+		TotalOrder:         req.TotalOrder,
+		IntermediateFormat: req.IntermediateFormat,
+		IntermediateCodec:  req.IntermediateCodec,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	if alreadySubmitted {
+		return &pb.JobSubmissionResponse{JobId: job.JobId, Response: "Job already submitted"}, nil
+	}
+
+	return &pb.JobSubmissionResponse{JobId: job.JobId, Response: "OK"}, nil
+}
+
+func (c *communicationHandler) GetJobStatus(ctx context.Context, req *pb.JobStatusRequest) (*pb.JobStatusResponse, error) {
+	job := c.jobs.GetJob(req.JobId)
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown job %s", req.JobId)
+	}
+
+	progress := job.SharedResources.GetProgress()
+
+	jobStatus, failureReason := c.jobs.GetJobStatus(job.JobId)
+
+	return &pb.JobStatusResponse{
+		JobId:           job.JobId,
+		JobStatus:       jobStatus,
+		FailureReason:   failureReason,
+		MapsToDo:        int32(progress.MapsToDo),
+		ReducesToDo:     int32(progress.ReducesToDo),
+		TasksInProgress: int32(progress.TasksInProgress),
+		ReclaimedTasks:  int32(progress.ReclaimedTasks),
+	}, nil
+}
+
+func (c *communicationHandler) GetJobConfig(ctx context.Context, req *pb.JobConfigRequest) (*pb.JobConfig, error) {
+	job := c.jobs.GetJob(req.JobId)
+	if job == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown job %s", req.JobId)
+	}
+
+	return utils.BuildJobConfig(job), nil
+}
+
+func (c *communicationHandler) ReportFailure(ctx context.Context, req *pb.IFailed) (*pb.IFailedResponse, error) {
+	log.Printf("Worker<%s> failed %s (attempt %d): %s", req.WorkerUuid, req.WorkFailed, req.Attempt, req.ErrorMessage)
+
+	job := c.jobs.GetJob(req.JobId)
+	if job == nil {
+		return &pb.IFailedResponse{Response: utils.ReportUnknownJob}, nil
+	}
+
+	if req.WorkType == utils.Reduce && len(req.MissingMapOutputs) > 0 {
+		return c.reportMissingMapOutputs(ctx, job, req)
+	}
+
+	result, quarantined := job.SharedResources.ReportFailure(req.WorkFailed, req.WorkType, req.WorkerUuid,
+		transport.PeerIdentity(ctx), uint32(req.Attempt), req.ErrorMessage)
+	if result != utils.ReportAccepted {
+		log.Printf("Ignoring failure of %s (attempt %d) from Worker<%s>: %s", req.WorkFailed, req.Attempt, req.WorkerUuid, result)
+		return &pb.IFailedResponse{Response: result, Accepted: false}, nil
+	}
+
+	if quarantined {
+		log.Printf("%s task %s of job %s quarantined after too many failures", req.WorkType, req.WorkFailed, job.JobId)
+		c.coordinator.handlePoisonTasks(job)
+	} else {
+		log.Printf("%s task %s of job %s requeued", req.WorkType, req.WorkFailed, job.JobId)
+	}
+
+	return &pb.IFailedResponse{Response: result, Accepted: true}, nil
+}
+
+// reportMissingMapOutputs atiende a un reduce al que le faltan salidas de maps: en vez de contarlo como fallo del
+// reduce, se vuelven a ejecutar los maps perdidos.
+func (c *communicationHandler) reportMissingMapOutputs(ctx context.Context, job *utils.Job, req *pb.IFailed) (*pb.IFailedResponse, error) {
+	result, reopened := job.SharedResources.ReportMissingMapOutputs(req.WorkFailed, req.WorkerUuid, transport.PeerIdentity(ctx),
+		uint32(req.Attempt), utils.ParseMapOutputs(req.MissingMapOutputs))
+	if result != utils.ReportAccepted {
+		log.Printf("Ignoring missing map outputs of %s (attempt %d) from Worker<%s>: %s", req.WorkFailed, req.Attempt, req.WorkerUuid, result)
+		return &pb.IFailedResponse{Response: result, Accepted: false}, nil
+	}
+
+	if len(reopened) > 0 {
+		log.Printf("Reduce task %s of job %s requeued, re-executing lost map tasks %v", req.WorkFailed, job.JobId, reopened)
+	} else {
+		log.Printf("Reduce task %s of job %s requeued, its lost map tasks were already requeued", req.WorkFailed, job.JobId)
 	}
 
-	return &pb.IFinishedResponse{Response: "OK"}, nil
+	return &pb.IFailedResponse{Response: result, Accepted: true}, nil
 }
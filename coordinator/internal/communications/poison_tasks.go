@@ -0,0 +1,77 @@
+package communications
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"tp1/coordinator/internal/utils"
+)
+
+type quarantinedTaskReport struct {
+	WorkName   string   `json:"workName"`
+	TaskType   string   `json:"taskType"`
+	Attempts   uint32   `json:"attempts"`
+	LostBy     []string `json:"lostBy,omitempty"`
+	LastErrors []string `json:"lastErrors,omitempty"`
+}
+
+type partialResultsManifest struct {
+	JobId             string                  `json:"jobId"`
+	SkippedInputs     []string                `json:"skippedInputs"`
+	MissingPartitions []string                `json:"missingPartitions"`
+	QuarantinedTasks  []quarantinedTaskReport `json:"quarantinedTasks"`
+}
+
+func poisonTasksReport(quarantined []utils.QuarantinedTask) string {
+	var badTasks []string
+	for _, quarantinedTask := range quarantined {
+		description := fmt.Sprintf("%s %s (%d attempts", quarantinedTask.Task.TaskType, quarantinedTask.WorkName,
+			quarantinedTask.Task.Attempt)
+		if splits := quarantinedTask.Task.Splits; len(splits) > 0 {
+			description += ", input " + utils.DescribeSplits(splits)
+		}
+		if failures := quarantinedTask.Task.Failures; len(failures) > 0 {
+			description += ", last error: " + failures[len(failures)-1].ErrorMessage
+		}
+		badTasks = append(badTasks, description+")")
+	}
+
+	return "tasks exceeded the retry limit: " + strings.Join(badTasks, "; ")
+}
+
+func writePartialResultsManifest(job *utils.Job, quarantined []utils.QuarantinedTask) (string, error) {
+	manifest := partialResultsManifest{JobId: job.JobId, SkippedInputs: []string{}, MissingPartitions: []string{}}
+
+	for _, quarantinedTask := range quarantined {
+		task := quarantinedTask.Task
+
+		if task.TaskType == utils.Map {
+			for _, split := range task.Splits {
+				manifest.SkippedInputs = append(manifest.SkippedInputs, split.String())
+			}
+		} else if task.TaskType == utils.Reduce {
+			manifest.MissingPartitions = append(manifest.MissingPartitions, fmt.Sprintf("%s-%d", job.OutputPrefix, task.TaskId))
+		}
+
+		report := quarantinedTaskReport{WorkName: quarantinedTask.WorkName, TaskType: task.TaskType,
+			Attempts: task.Attempt, LostBy: task.LostBy}
+		for _, failure := range task.Failures {
+			report.LastErrors = append(report.LastErrors, failure.ErrorMessage)
+		}
+		manifest.QuarantinedTasks = append(manifest.QuarantinedTasks, report)
+	}
+
+	manifestPath := job.OutputPrefix + ".manifest.json"
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifestPath, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0755); err != nil {
+		return manifestPath, err
+	}
+
+	return manifestPath, os.WriteFile(manifestPath, append(content, '\n'), 0644)
+}
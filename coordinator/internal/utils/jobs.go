@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"path/filepath"
+	"sync"
+	"tp1/coordinator/internal/wal"
+)
+
+type Job struct {
+	JobId              string
+	Generation         int
+	InputFiles         []string
+	ReducerAmount      int
+	MapAmount          int
+	Plugin             string
+	OutputPrefix       string
+	TotalOrder         bool
+	IntermediateFormat string
+	IntermediateCodec  string
+	JobStatus          string
+	FailureReason      string
+	SharedResources    *SharedResources
+	StateLog           *wal.Log
+}
+
+type JobTable struct {
+	mutex     sync.Mutex
+	jobs      map[string]*Job
+	jobsOrder []string
+}
+
+func IntermediateDir(jobId string) string {
+	return filepath.Join("intermediate", jobId)
+}
+
+func CreateJobTable() *JobTable {
+	return &JobTable{jobs: make(map[string]*Job)}
+}
+
+func (jt *JobTable) AddJob(job *Job) {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	if _, exists := jt.jobs[job.JobId]; !exists {
+		jt.jobsOrder = append(jt.jobsOrder, job.JobId)
+	}
+	jt.jobs[job.JobId] = job
+}
+
+func (jt *JobTable) GetJob(jobId string) *Job {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	return jt.jobs[jobId]
+}
+
+func (jt *JobTable) GetRunningJobs() []*Job {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	var runningJobs []*Job
+	for _, jobId := range jt.jobsOrder {
+		if job := jt.jobs[jobId]; job.JobStatus == JobRunning {
+			runningJobs = append(runningJobs, job)
+		}
+	}
+
+	return runningJobs
+}
+
+func (jt *JobTable) GetAndAssignAvailableWork(workerUuid string, workerIdentity string) (*Job, *WorkToDo) {
+	for _, job := range jt.GetRunningJobs() {
+		if workToDo := job.SharedResources.GetAndAssignAvailableWork(workerUuid, workerIdentity); workToDo != nil {
+			return job, workToDo
+		}
+	}
+
+	return nil, nil
+}
+
+func (jt *JobTable) MarkJobAsCompleted(jobId string) bool {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	job, exists := jt.jobs[jobId]
+	if !exists || job.JobStatus != JobRunning {
+		return false
+	}
+
+	job.JobStatus = JobCompleted
+	return true
+}
+
+func (jt *JobTable) MarkJobAsFailed(jobId string, failureReason string) bool {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	job, exists := jt.jobs[jobId]
+	if !exists || job.JobStatus != JobRunning {
+		return false
+	}
+
+	job.JobStatus = JobFailed
+	job.FailureReason = failureReason
+	return true
+}
+
+func (jt *JobTable) IsJobRunning(jobId string) bool {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	job, exists := jt.jobs[jobId]
+	return exists && job.JobStatus == JobRunning
+}
+
+func (jt *JobTable) GetJobStatus(jobId string) (string, string) {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	job := jt.jobs[jobId]
+	return job.JobStatus, job.FailureReason
+}
+
+func (jt *JobTable) HasFailedJobs() bool {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	for _, job := range jt.jobs {
+		if job.JobStatus == JobFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (jt *JobTable) AreAllJobsDone() bool {
+	jt.mutex.Lock()
+	defer jt.mutex.Unlock()
+
+	for _, job := range jt.jobs {
+		if job.JobStatus == JobRunning {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,28 @@
+package utils
+
+import "fmt"
+
+// Límites de tamaño de un job. Cada reduce recibe en su asignación un elemento por map (unos 12 bytes), y con
+// MaxMapTasks la asignación, igual que la lista de salidas perdidas que puede devolver, entra en los 4 MB que gRPC
+// acepta por defecto en un mensaje.
+const MaxMapTasks = 200000
+const MaxReducers = 100000
+
+func (config SchedulingConfig) Validate() error {
+	if config.LeaseDuration <= 0 {
+		return fmt.Errorf("the lease duration must be positive, got %v", config.LeaseDuration)
+	}
+	if config.MaxAttempts < 1 {
+		return fmt.Errorf("a task needs at least one attempt, got max attempts %d", config.MaxAttempts)
+	}
+	if config.MaxFailures < 1 {
+		return fmt.Errorf("a task must be allowed at least one failure, got max failures %d", config.MaxFailures)
+	}
+	if config.SpeculationThreshold < 0 {
+		return fmt.Errorf("the speculation threshold can't be negative, got %d", config.SpeculationThreshold)
+	}
+	if config.SpeculationDelay < 0 {
+		return fmt.Errorf("the speculation delay can't be negative, got %v", config.SpeculationDelay)
+	}
+	return nil
+}
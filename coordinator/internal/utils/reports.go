@@ -0,0 +1,7 @@
+package utils
+
+const ReportAccepted = "OK"
+const ReportDuplicate = "Duplicate"
+const ReportStale = "Stale"
+const ReportUnknownWork = "Unknown work"
+const ReportUnknownJob = "Unknown job"
@@ -1,8 +1,75 @@
 package utils
 
-import pb "tp1/protocol/messages"
+import (
+	pb "tp1/protocol/messages"
+)
 
-func BuildAskForWorkResponse(assignedTask string, workerId int32, workType string, reducerAmount uint8) *pb.AskForWorkResponse {
-	return &pb.AskForWorkResponse{FilePath: assignedTask, WorkType: workType,
-		WorkerId: workerId, ReducerNumber: int32(reducerAmount)}
+func BuildAskForWorkResponse(job *Job, workToDo *WorkToDo) *pb.AskForWorkResponse {
+	mapOutputs, mapOutputLocations := buildMapOutputs(workToDo.MapOutputs)
+	return &pb.AskForWorkResponse{FilePath: workToDo.WorkName, WorkType: workToDo.Task.TaskType,
+		TaskId: int32(workToDo.Task.TaskId), JobId: job.JobId, JobGeneration: int32(job.Generation), Attempt: int32(workToDo.Attempt),
+		PartitionBounds: workToDo.PartitionBounds, InputSplits: buildInputSplits(workToDo.Task.Splits),
+		MapOutputs: mapOutputs, MapOutputLocations: mapOutputLocations, SkippedMapTaskIds: toInt32s(workToDo.SkippedMaps)}
+}
+
+func BuildJobConfig(job *Job) *pb.JobConfig {
+	return &pb.JobConfig{JobId: job.JobId, Generation: int32(job.Generation), ReducerNumber: int32(job.ReducerAmount), MapNumber: int32(job.MapAmount),
+		Plugin: job.Plugin, OutputPrefix: job.OutputPrefix, TotalOrder: job.TotalOrder,
+		IntermediateFormat: job.IntermediateFormat, IntermediateCodec: job.IntermediateCodec}
+}
+
+func buildInputSplits(splits []InputSplit) []*pb.InputSplit {
+	inputSplits := make([]*pb.InputSplit, len(splits))
+	for i, split := range splits {
+		inputSplits[i] = &pb.InputSplit{Path: split.Path, Offset: split.Offset, Length: split.Length}
+	}
+	return inputSplits
+}
+
+// buildMapOutputs arma la lista de maps terminados que lee un reduce. Cada ubicación se envía una sola vez y los maps
+// la referencian por posición: todo reduce recibe un elemento por map, así que cada uno tiene que ocupar pocos bytes.
+func buildMapOutputs(mapOutputs []MapOutput) ([]*pb.MapOutput, []string) {
+	if len(mapOutputs) == 0 {
+		return nil, nil
+	}
+
+	outputs := make([]*pb.MapOutput, len(mapOutputs))
+	var locations []string
+	locationIndexes := make(map[string]int32)
+	for i, mapOutput := range mapOutputs {
+		outputs[i] = &pb.MapOutput{MapTaskId: int32(mapOutput.MapTaskId), Attempt: int32(mapOutput.Attempt)}
+		if mapOutput.Location == "" {
+			continue
+		}
+
+		index, exists := locationIndexes[mapOutput.Location]
+		if !exists {
+			locations = append(locations, mapOutput.Location)
+			index = int32(len(locations))
+			locationIndexes[mapOutput.Location] = index
+		}
+		outputs[i].LocationIndex = index
+	}
+	return outputs, locations
+}
+
+// ParseMapOutputs convierte las salidas de maps que reporta un worker.
+func ParseMapOutputs(mapOutputs []*pb.MapOutput) []MapOutput {
+	parsed := make([]MapOutput, len(mapOutputs))
+	for i, mapOutput := range mapOutputs {
+		parsed[i] = MapOutput{MapTaskId: int(mapOutput.MapTaskId), Attempt: uint32(mapOutput.Attempt)}
+	}
+	return parsed
+}
+
+func toInt32s(values []int) []int32 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	converted := make([]int32, len(values))
+	for i, value := range values {
+		converted[i] = int32(value)
+	}
+	return converted
 }
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildMapOutputs(t *testing.T) {
+	mapOutputs := []MapOutput{
+		{MapTaskId: 1, Attempt: 1, Location: "unix:///tmp/worker-a.sock"},
+		{MapTaskId: 2, Attempt: 3, Location: "unix:///tmp/worker-b.sock"},
+		{MapTaskId: 3, Attempt: 1, Location: "unix:///tmp/worker-a.sock"},
+		{MapTaskId: 4, Attempt: 2},
+	}
+
+	outputs, locations := buildMapOutputs(mapOutputs)
+
+	if len(locations) != 2 {
+		t.Fatalf("se enviaron las ubicaciones %v, se esperaba cada worker una sola vez", locations)
+	}
+	for i, output := range outputs {
+		mapOutput := mapOutputs[i]
+		if output.MapTaskId != int32(mapOutput.MapTaskId) || output.Attempt != int32(mapOutput.Attempt) {
+			t.Errorf("salida %d: map %d intento %d, se esperaba map %d intento %d", i, output.MapTaskId,
+				output.Attempt, mapOutput.MapTaskId, mapOutput.Attempt)
+		}
+
+		location := ""
+		if output.LocationIndex > 0 {
+			location = locations[output.LocationIndex-1]
+		}
+		if location != mapOutput.Location {
+			t.Errorf("el map %d apunta a %q, se esperaba %q", mapOutput.MapTaskId, location, mapOutput.Location)
+		}
+	}
+
+	if outputs, locations := buildMapOutputs(nil); outputs != nil || locations != nil {
+		t.Errorf("sin maps terminados se esperaba una lista vacía, se obtuvo %v y %v", outputs, locations)
+	}
+}
+
+// La asignación de un reduce lleva un elemento por map: con MaxMapTasks maps, y aunque cada uno haya corrido en un
+// worker distinto de unos cuantos, tiene que entrar en los 4 MB que gRPC acepta por defecto.
+func TestReduceAssignmentFitsInAMessageAtMaxMapTasks(t *testing.T) {
+	const workers = 1000
+	const defaultMaxMessageSize = 4 << 20
+
+	mapOutputs := make([]MapOutput, MaxMapTasks)
+	for i := range mapOutputs {
+		mapOutputs[i] = MapOutput{MapTaskId: i + 1, Attempt: uint32(1 + i%10),
+			Location: fmt.Sprintf("unix:///tmp/mr-shuffle-%036d.sock", i%workers)}
+	}
+	job := &Job{JobId: "job-0123456789ab", Generation: 1}
+	work := &WorkToDo{WorkName: "mr-x-1", Task: Task{TaskType: Reduce, TaskId: MaxReducers}, Attempt: 1,
+		MapOutputs: mapOutputs}
+
+	size := proto.Size(BuildAskForWorkResponse(job, work))
+	if size > defaultMaxMessageSize {
+		t.Errorf("la asignación de un reduce con %d maps ocupa %d bytes, más que los %d de un mensaje gRPC",
+			MaxMapTasks, size, defaultMaxMessageSize)
+	}
+	t.Logf("asignación con %d maps: %d bytes", MaxMapTasks, size)
+}
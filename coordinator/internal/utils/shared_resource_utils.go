@@ -2,63 +2,242 @@ package utils
 
 import (
 	"log"
+	"sort"
 	"time"
+	"tp1/coordinator/internal/wal"
 )
 
-func (sr *SharedResources) getFirstAvailableMappingTask() (*string, *Task) {
+func (sr *SharedResources) getFirstAvailableTask(taskType string) (*string, *Task) {
 
-	var taskName string
-	var taskToDo Task
-
-	for fileSplit, task := range sr.tasksMap {
-		if (task.TaskType == Map) && (task.TaskStatus == NotAssigned) {
-			taskName = fileSplit
-			taskToDo = task
-			break
-		} else if (task.TaskType == Map) && (task.TimeStamp != nil) && (task.TaskStatus == Assigned) {
-			if time.Since(*task.TimeStamp) > 10*time.Second {
-				log.Printf("A worker died!")
-				taskName = fileSplit
-				taskToDo = task
-				break
-			}
+	for workName, task := range sr.tasksMap {
+		if (task.TaskType == taskType) && (task.TaskStatus == NotAssigned) {
+			return &workName, &task
 		}
 	}
 
-	return &taskName, &taskToDo
+	return nil, nil
 }
 
-func (sr *SharedResources) getFirstAvailableReduceTask() (*string, *Task) {
+// getSlowestStragglerTask elige, entre las tareas en curso de la fase, la que lleva más tiempo corriendo sin copia de
+// respaldo, para lanzarle un intento especulativo en otro worker.
+func (sr *SharedResources) getSlowestStragglerTask(taskType string, workerUuid string) (*string, *Task) {
 
-	var taskName string
-	var taskToDo Task
+	var slowestName *string
+	var slowestTask *Task
+	now := time.Now()
 
-	for fileSplit, task := range sr.tasksMap {
-		if (task.TaskType == Reduce) && (task.TaskStatus == NotAssigned) {
-			taskName = fileSplit
-			taskToDo = task
-			break
-		} else if (task.TaskType == Reduce) && (task.TimeStamp != nil) && (task.TaskStatus == Assigned) {
-			if time.Since(*task.TimeStamp) > 10*time.Second {
-				log.Printf("A worker died!")
-				taskName = fileSplit
-				taskToDo = task
-				break
-			}
+	for workName, task := range sr.tasksMap {
+		if task.TaskType != taskType || task.TaskStatus != Assigned || len(task.Assignments) != 1 ||
+			task.CommittingAttempt != 0 {
+			continue
+		}
+
+		assignment := task.Assignments[0]
+		if assignment.WorkerUuid == workerUuid || now.Sub(assignment.TimeStamp) < sr.config.SpeculationDelay {
+			continue
+		}
+
+		if slowestTask == nil || assignment.TimeStamp.Before(slowestTask.Assignments[0].TimeStamp) {
+			slowestName, slowestTask = &workName, &task
 		}
 	}
 
-	return &taskName, &taskToDo
+	return slowestName, slowestTask
 }
 
-func (sr *SharedResources) assignTask(workToAssign, workerUuid string) {
+func (sr *SharedResources) assignTask(workToAssign, workerUuid string, workerIdentity string) uint32 {
 
 	currentTime := time.Now()
 
 	task := sr.tasksMap[workToAssign]
 	task.TaskStatus = Assigned
-	task.TimeStamp = &currentTime
-	task.AssignedWorker = &workerUuid
+	task.Attempt += 1
+
+	assignment := Assignment{WorkerUuid: workerUuid, Attempt: task.Attempt, TimeStamp: currentTime,
+		LeaseExpiration: currentTime.Add(sr.config.LeaseDuration)}
+	if workerIdentity != "" {
+		assignment.WorkerIdentity = &workerIdentity
+	}
+	task.Assignments = append(append([]Assignment(nil), task.Assignments...), assignment)
 	sr.tasksMap[workToAssign] = task
 
+	sr.record(wal.Entry{Operation: wal.Assign, WorkName: workToAssign, WorkerUuid: workerUuid,
+		WorkerIdentity: workerIdentity, Attempt: task.Attempt})
+
+	return task.Attempt
+}
+
+func (sr *SharedResources) reclaimAssignment(workToReclaim string, attempt uint32) ReclaimedTask {
+
+	task := sr.tasksMap[workToReclaim]
+
+	lostBy := ""
+	for i, assignment := range task.Assignments {
+		if assignment.Attempt == attempt {
+			lostBy = assignment.WorkerUuid
+			task = releaseAssignment(task, i)
+			break
+		}
+	}
+
+	task.LostBy = append(task.LostBy, lostBy)
+	sr.tasksMap[workToReclaim] = task
+	sr.reclaimedTasks += 1
+
+	sr.record(wal.Entry{Operation: wal.Reclaim, WorkName: workToReclaim, WorkerUuid: lostBy, Attempt: attempt})
+
+	quarantined := task.TaskStatus == NotAssigned && sr.isPoisoned(task)
+	if quarantined {
+		sr.quarantineTask(workToReclaim)
+	}
+
+	return ReclaimedTask{WorkName: workToReclaim, TaskType: task.TaskType, LostBy: lostBy, Quarantined: quarantined}
+}
+
+func (sr *SharedResources) isPoisoned(task Task) bool {
+	return task.Attempt >= sr.config.MaxAttempts || len(task.Failures) >= sr.config.MaxFailures
+}
+
+func (sr *SharedResources) quarantineTask(workToQuarantine string) {
+
+	task := sr.tasksMap[workToQuarantine]
+	task.TaskStatus = Quarantined
+	sr.tasksMap[workToQuarantine] = task
+
+	if sr.config.SkipPoisonTasks {
+		sr.decrementWorkToDo(task.TaskType)
+		sr.computePartitionBoundsIfSampled()
+	}
+
+	sr.record(wal.Entry{Operation: wal.Quarantine, WorkName: workToQuarantine, Attempt: task.Attempt})
+}
+
+// releaseAssignment quita el intento de la tarea. Si era el último intento en curso la tarea vuelve a quedar libre.
+func releaseAssignment(task Task, assignmentIndex int) Task {
+	released := task.Assignments[assignmentIndex]
+
+	assignments := make([]Assignment, 0, len(task.Assignments)-1)
+	assignments = append(assignments, task.Assignments[:assignmentIndex]...)
+	assignments = append(assignments, task.Assignments[assignmentIndex+1:]...)
+	task.Assignments = assignments
+
+	if task.CommittingAttempt == released.Attempt {
+		task.CommittingAttempt = 0
+	}
+
+	if len(task.Assignments) == 0 {
+		task.TaskStatus = NotAssigned
+		task.Assignments = nil
+	}
+
+	return task
+}
+
+// findAssignment devuelve la posición del intento en curso que corresponde al worker, o -1 si ya no lo tiene.
+func findAssignment(task Task, workerUuid string, workerIdentity string, attempt uint32) int {
+	if task.TaskStatus != Assigned {
+		return -1
+	}
+
+	for i, assignment := range task.Assignments {
+		if assignment.WorkerIdentity != nil && *assignment.WorkerIdentity != workerIdentity {
+			continue
+		}
+
+		if assignment.WorkerUuid == workerUuid && assignment.Attempt == attempt {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (sr *SharedResources) isLeaseExpired(assignment Assignment) bool {
+	return time.Now().After(assignment.LeaseExpiration)
+}
+
+// computePartitionBoundsIfSampled elige, una vez terminado el muestreo, los límites de las particiones por rango:
+// los cuantiles de todas las claves muestreadas, de modo que cada reducer reciba una porción parecida.
+func (sr *SharedResources) computePartitionBoundsIfSampled() {
+	if !sr.totalOrder || sr.samplesToDo > 0 || sr.partitionBounds != nil {
+		return
+	}
+
+	var samples []string
+	for _, task := range sr.tasksMap {
+		if task.TaskType == Sample {
+			samples = append(samples, task.SampleKeys...)
+		}
+	}
+	sort.Strings(samples)
+
+	bounds := make([]string, 0, max(sr.reducerAmount-1, 0))
+	for i := 1; i < sr.reducerAmount && len(samples) > 0; i++ {
+		bounds = append(bounds, samples[i*len(samples)/sr.reducerAmount])
+	}
+	sr.partitionBounds = bounds
+
+	log.Printf("Sampling finished with %d keys, partition bounds: %v", len(samples), bounds)
+}
+
+func (sr *SharedResources) decrementWorkToDo(taskType string) {
+	if taskType == Sample && sr.samplesToDo > 0 {
+		sr.samplesToDo -= 1
+	}
+
+	if taskType == Map && sr.mapsToDo > 0 {
+		sr.mapsToDo -= 1
+	}
+
+	if taskType == Reduce && sr.reducesToDo > 0 {
+		sr.reducesToDo -= 1
+	}
+}
+
+func (sr *SharedResources) record(entry wal.Entry) {
+	if err := sr.stateLog.Append(entry); err != nil {
+		log.Printf("Could not persist %s of %s: %v", entry.Operation, entry.WorkName, err)
+	}
+}
+
+// committedMapOutputs devuelve, ordenados por tarea, los intentos de los maps terminados y los maps omitidos por
+// cuarentena, así un reduce nunca lee archivos que no fueron confirmados por el coordinator y puede verificar que no
+// le falte ninguno.
+func (sr *SharedResources) committedMapOutputs() ([]MapOutput, []int) {
+	mapOutputs := make([]MapOutput, 0, len(sr.tasksMap))
+	var skippedMaps []int
+	for _, task := range sr.tasksMap {
+		if task.TaskType != Map {
+			continue
+		}
+		if task.TaskStatus == Finished {
+			mapOutputs = append(mapOutputs, MapOutput{MapTaskId: task.TaskId, Attempt: task.CommittedAttempt,
+				Location: task.OutputLocation})
+		} else if task.TaskStatus == Quarantined {
+			skippedMaps = append(skippedMaps, task.TaskId)
+		}
+	}
+	sort.Slice(mapOutputs, func(i, j int) bool {
+		return mapOutputs[i].MapTaskId < mapOutputs[j].MapTaskId
+	})
+	sort.Ints(skippedMaps)
+	return mapOutputs, skippedMaps
+}
+
+// reopenMapTask vuelve a encolar un map terminado cuyas salidas se perdieron. Si el intento ya no es el confirmado
+// (otro reduce ya lo reportó y el map se volvió a ejecutar) no hace nada.
+func (sr *SharedResources) reopenMapTask(mapName string, lostAttempt uint32) bool {
+	task, exists := sr.tasksMap[mapName]
+	if !exists || task.TaskType != Map || task.TaskStatus != Finished || task.CommittedAttempt != lostAttempt {
+		return false
+	}
+
+	task.TaskStatus = NotAssigned
+	task.CommittedAttempt = 0
+	task.OutputLocation = ""
+	sr.tasksMap[mapName] = task
+	sr.mapsToDo += 1
+
+	sr.record(wal.Entry{Operation: wal.Reopen, WorkName: mapName, Attempt: lostAttempt})
+	return true
 }
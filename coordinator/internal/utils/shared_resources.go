@@ -2,105 +2,445 @@ package utils
 
 import (
 	"log"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
+	"tp1/coordinator/internal/wal"
 )
 
 type Task struct {
-	TaskId         uint8
-	TaskType       string
-	TaskStatus     string
-	AssignedWorker *string
-	TimeStamp      *time.Time
+	TaskId            int
+	TaskType          string
+	TaskStatus        string
+	Splits            []InputSplit
+	Attempt           uint32
+	Assignments       []Assignment
+	CommittingAttempt uint32
+	CommittedAttempt  uint32
+	OutputLocation    string
+	LostBy            []string
+	Failures          []TaskFailure
+	SampleKeys        []string
+}
+
+type Assignment struct {
+	WorkerUuid      string
+	WorkerIdentity  *string
+	Attempt         uint32
+	TimeStamp       time.Time
+	LeaseExpiration time.Time
+}
+
+type TaskFailure struct {
+	WorkerUuid   string
+	Attempt      uint32
+	ErrorMessage string
+}
+
+type SchedulingConfig struct {
+	LeaseDuration        time.Duration
+	MaxAttempts          uint32
+	MaxFailures          int
+	SkipPoisonTasks      bool
+	SpeculationThreshold int
+	SpeculationDelay     time.Duration
 }
 
 type SharedResources struct {
-	mutex         sync.Mutex
-	mapsToDo      uint8
-	reducesToDo   uint8
-	reducerAmount uint8
-	tasksMap      map[string]Task
+	mutex            sync.Mutex
+	samplesToDo      int
+	mapsToDo         int
+	reducesToDo      int
+	reducerAmount    int
+	totalOrder       bool
+	partitionBounds  []string
+	config           SchedulingConfig
+	reclaimedTasks   uint
+	speculativeTasks uint
+	tasksMap         map[string]Task
+	stateLog         *wal.Log
+}
+
+type ReclaimedTask struct {
+	WorkName    string
+	TaskType    string
+	LostBy      string
+	Quarantined bool
+}
+
+type QuarantinedTask struct {
+	WorkName string
+	Task     Task
+}
+
+type Progress struct {
+	SamplesToDo      int
+	MapsToDo         int
+	ReducesToDo      int
+	TasksInProgress  uint
+	ReclaimedTasks   uint
+	QuarantinedTasks uint
+	SpeculativeTasks uint
+}
+
+// MapOutput identifica las particiones que escribió el intento de un map que quedó registrado como terminado.
+type MapOutput struct {
+	MapTaskId int
+	Attempt   uint32
+	Location  string
 }
 
 type WorkToDo struct {
-	WorkName      string
-	Task          Task
-	ReducerAmount uint8
+	WorkName        string
+	Task            Task
+	Attempt         uint32
+	Speculative     bool
+	PartitionBounds []string
+	MapOutputs      []MapOutput
+	SkippedMaps     []int
 }
 
-func CreateInitialSharedResources(fileSplits []string, reducerAmount uint8) *SharedResources {
+func CreateInitialSharedResources(mapInputs [][]InputSplit, reducerAmount int, totalOrder bool, config SchedulingConfig,
+	stateLog *wal.Log) *SharedResources {
 
 	taskMap := make(map[string]Task)
 
 	i := 1
-	for _, fileSplit := range fileSplits {
-		taskMap[fileSplit] = Task{TaskId: uint8(i), TaskStatus: NotAssigned, AssignedWorker: nil,
-			TimeStamp: nil, TaskType: Map}
+	for _, splits := range mapInputs {
+		taskMap["mr-map-"+strconv.Itoa(i)] = Task{TaskId: i, TaskStatus: NotAssigned, TaskType: Map, Splits: splits}
 		i += 1
 	}
 
+	// En modo orden total, antes de los maps cada entrada se muestrea para elegir los límites de las particiones
+	samplesToDo := 0
+	if totalOrder {
+		for i, splits := range mapInputs {
+			taskMap["mr-sample-"+strconv.Itoa(i+1)] = Task{TaskId: i + 1, TaskStatus: NotAssigned, TaskType: Sample,
+				Splits: splits}
+		}
+		samplesToDo = len(mapInputs)
+	}
+
 	reducerNumber := 1
 	for range reducerAmount {
 		fileName := "mr-x-" + strconv.Itoa(reducerNumber)
-		taskMap[fileName] = Task{TaskId: uint8(reducerNumber), TaskStatus: NotAssigned, AssignedWorker: nil,
-			TimeStamp: nil, TaskType: Reduce}
+		taskMap[fileName] = Task{TaskId: reducerNumber, TaskStatus: NotAssigned, TaskType: Reduce}
 		reducerNumber += 1
 	}
 
 	return &SharedResources{
 		tasksMap:      taskMap,
-		mapsToDo:      uint8(len(fileSplits)),
+		samplesToDo:   samplesToDo,
+		mapsToDo:      len(mapInputs),
 		reducesToDo:   reducerAmount,
 		reducerAmount: reducerAmount,
+		totalOrder:    totalOrder,
+		config:        config,
+		stateLog:      stateLog,
 	}
 }
 
-func (sr *SharedResources) GetAndAssignAvailableWork(workerUuid string) *WorkToDo {
+func (sr *SharedResources) Restore(entries []wal.Entry) {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	for _, entry := range entries {
+		task, exists := sr.tasksMap[entry.WorkName]
+		if !exists {
+			continue
+		}
+
+		switch entry.Operation {
+		case wal.Finish:
+			if task.TaskStatus != Finished {
+				sr.decrementWorkToDo(task.TaskType)
+			}
+			task.TaskStatus = Finished
+			task.CommittedAttempt = entry.Attempt
+			task.OutputLocation = entry.Location
+			task.SampleKeys = entry.SampleKeys
+		case wal.Assign:
+			task.Attempt = max(task.Attempt, entry.Attempt)
+		case wal.Reclaim:
+			task.LostBy = append(task.LostBy, entry.WorkerUuid)
+			sr.reclaimedTasks += 1
+		case wal.Fail:
+			task.Failures = append(task.Failures, TaskFailure{WorkerUuid: entry.WorkerUuid, Attempt: entry.Attempt,
+				ErrorMessage: entry.ErrorMessage})
+		case wal.Quarantine:
+			if task.TaskStatus != Quarantined && sr.config.SkipPoisonTasks {
+				sr.decrementWorkToDo(task.TaskType)
+			}
+			task.TaskStatus = Quarantined
+		case wal.Reopen:
+			if task.TaskStatus == Finished {
+				sr.mapsToDo += 1
+			}
+			task.TaskStatus = NotAssigned
+			task.CommittedAttempt = 0
+			task.OutputLocation = ""
+		}
+
+		sr.tasksMap[entry.WorkName] = task
+	}
+
+	sr.computePartitionBoundsIfSampled()
+}
+
+func (sr *SharedResources) GetAndAssignAvailableWork(workerUuid string, workerIdentity string) *WorkToDo {
 	sr.mutex.Lock()
 	defer sr.mutex.Unlock()
 
 	var workName *string
 	var workToDo *Task
+	var phase string
+	var remaining int
 
-	if sr.mapsToDo > 0 {
-		workName, workToDo = sr.getFirstAvailableMappingTask()
+	if sr.samplesToDo > 0 {
+		workName, workToDo = sr.getFirstAvailableTask(Sample)
+		phase, remaining = Sample, sr.samplesToDo
+	} else if sr.mapsToDo > 0 {
+		workName, workToDo = sr.getFirstAvailableTask(Map)
+		phase, remaining = Map, sr.mapsToDo
 	} else if (sr.mapsToDo == 0) && (sr.reducesToDo > 0) {
-		workName, workToDo = sr.getFirstAvailableReduceTask()
+		workName, workToDo = sr.getFirstAvailableTask(Reduce)
+		phase, remaining = Reduce, sr.reducesToDo
 	} else {
 		log.Printf("There is no more work to do!!")
 		return nil
 	}
 
+	speculative := false
+	if (workName == nil || workToDo == nil) && remaining <= sr.config.SpeculationThreshold {
+		workName, workToDo = sr.getSlowestStragglerTask(phase, workerUuid)
+		speculative = true
+	}
+
 	if workName == nil || workToDo == nil {
 		return nil
 	}
 
-	sr.assignTask(*workName, workerUuid)
+	attempt := sr.assignTask(*workName, workerUuid, workerIdentity)
+	if speculative {
+		sr.speculativeTasks += 1
+	}
+
+	work := &WorkToDo{WorkName: *workName, Task: sr.tasksMap[*workName], Attempt: attempt, Speculative: speculative,
+		PartitionBounds: sr.partitionBounds}
+	if work.Task.TaskType == Reduce {
+		work.MapOutputs, work.SkippedMaps = sr.committedMapOutputs()
+	}
 
-	return &WorkToDo{WorkName: *workName, Task: *workToDo, ReducerAmount: 3}
+	return work
 }
 
-func (sr *SharedResources) MarkWorkAsFinished(workToMark string, workType string) {
+func (sr *SharedResources) MarkWorkAsFinished(workToMark string, workType string, workerUuid string, workerIdentity string,
+	attempt uint32, sampleKeys []string, location string) string {
 	sr.mutex.Lock()
 	defer sr.mutex.Unlock()
 
-	if workType == Map && sr.mapsToDo > 0 {
-		sr.mapsToDo -= 1
+	task, exists := sr.tasksMap[workToMark]
+	if !exists || task.TaskType != workType {
+		return ReportUnknownWork
+	}
+
+	if task.TaskStatus == Finished {
+		return ReportDuplicate
+	}
+
+	if findAssignment(task, workerUuid, workerIdentity, attempt) < 0 {
+		return ReportStale
 	}
 
-	if workType == Reduce && sr.reducesToDo > 0 {
-		sr.reducesToDo -= 1
+	if task.CommittingAttempt != 0 && task.CommittingAttempt != attempt {
+		return ReportStale
 	}
 
-	task := sr.tasksMap[workToMark]
+	sr.decrementWorkToDo(workType)
+
 	task.TaskStatus = Finished
+	task.Assignments = nil
+	task.CommittingAttempt = 0
+	task.CommittedAttempt = attempt
+	if workType == Sample {
+		task.SampleKeys = sampleKeys
+	}
+	if workType == Map {
+		task.OutputLocation = location
+	}
 	sr.tasksMap[workToMark] = task
+
+	sr.record(wal.Entry{Operation: wal.Finish, WorkName: workToMark, WorkerUuid: workerUuid, Attempt: attempt,
+		SampleKeys: task.SampleKeys, Location: task.OutputLocation})
+
+	sr.computePartitionBoundsIfSampled()
+
+	return ReportAccepted
+}
+
+func (sr *SharedResources) ReportFailure(workFailed string, workType string, workerUuid string, workerIdentity string,
+	attempt uint32, errorMessage string) (string, bool) {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	task, exists := sr.tasksMap[workFailed]
+	if !exists || task.TaskType != workType {
+		return ReportUnknownWork, false
+	}
+
+	if task.TaskStatus == Finished {
+		return ReportDuplicate, false
+	}
+
+	assignmentIndex := findAssignment(task, workerUuid, workerIdentity, attempt)
+	if assignmentIndex < 0 {
+		return ReportStale, false
+	}
+
+	task = releaseAssignment(task, assignmentIndex)
+	task.Failures = append(task.Failures, TaskFailure{WorkerUuid: workerUuid, Attempt: attempt, ErrorMessage: errorMessage})
+	sr.tasksMap[workFailed] = task
+
+	sr.record(wal.Entry{Operation: wal.Fail, WorkName: workFailed, WorkerUuid: workerUuid, Attempt: attempt,
+		ErrorMessage: errorMessage})
+
+	if task.TaskStatus == NotAssigned && sr.isPoisoned(task) {
+		sr.quarantineTask(workFailed)
+		return ReportAccepted, true
+	}
+
+	return ReportAccepted, false
+}
+
+// ReportMissingMapOutputs registra que un reduce no encontró algunas de las salidas de maps que le indicamos. No
+// cuenta como un fallo del reduce: se lo vuelve a encolar y los maps cuyas salidas se perdieron se vuelven a
+// ejecutar, por lo que los reduces no se asignan de nuevo hasta que esos maps terminen. Devuelve los maps reabiertos.
+func (sr *SharedResources) ReportMissingMapOutputs(workFailed string, workerUuid string, workerIdentity string,
+	attempt uint32, missing []MapOutput) (string, []string) {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	task, exists := sr.tasksMap[workFailed]
+	if !exists || task.TaskType != Reduce {
+		return ReportUnknownWork, nil
+	}
+
+	if task.TaskStatus == Finished {
+		return ReportDuplicate, nil
+	}
+
+	assignmentIndex := findAssignment(task, workerUuid, workerIdentity, attempt)
+	if assignmentIndex < 0 {
+		return ReportStale, nil
+	}
+
+	sr.tasksMap[workFailed] = releaseAssignment(task, assignmentIndex)
+
+	var reopened []string
+	for _, mapOutput := range missing {
+		mapName := "mr-map-" + strconv.Itoa(mapOutput.MapTaskId)
+		if sr.reopenMapTask(mapName, mapOutput.Attempt) {
+			reopened = append(reopened, mapName)
+		}
+	}
+
+	return ReportAccepted, reopened
+}
+
+// RenewLease extiende el lease del intento. Si el worker está por confirmar su salida (committing), además se le
+// concede el commit de la tarea en exclusiva: entre varios intentos en paralelo solo el primero en pedirlo gana.
+func (sr *SharedResources) RenewLease(workInProgress string, workerUuid string, workerIdentity string, attempt uint32,
+	committing bool) bool {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	task, exists := sr.tasksMap[workInProgress]
+	if !exists {
+		return false
+	}
+
+	assignmentIndex := findAssignment(task, workerUuid, workerIdentity, attempt)
+	if assignmentIndex < 0 {
+		return false
+	}
+
+	if task.CommittingAttempt != 0 && task.CommittingAttempt != attempt {
+		return false
+	}
+
+	if committing {
+		task.CommittingAttempt = attempt
+	}
+
+	assignments := append([]Assignment(nil), task.Assignments...)
+	assignments[assignmentIndex].LeaseExpiration = time.Now().Add(sr.config.LeaseDuration)
+	task.Assignments = assignments
+	sr.tasksMap[workInProgress] = task
+
+	return true
+}
+
+func (sr *SharedResources) ReclaimExpiredTasks() []ReclaimedTask {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	var reclaimed []ReclaimedTask
+
+	for workName, task := range sr.tasksMap {
+		if task.TaskStatus != Assigned {
+			continue
+		}
+
+		for _, assignment := range task.Assignments {
+			if sr.isLeaseExpired(assignment) {
+				reclaimed = append(reclaimed, sr.reclaimAssignment(workName, assignment.Attempt))
+			}
+		}
+	}
+
+	return reclaimed
+}
+
+func (sr *SharedResources) GetProgress() Progress {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	var tasksInProgress uint
+	var quarantinedTasks uint
+	for _, task := range sr.tasksMap {
+		if task.TaskStatus == Assigned {
+			tasksInProgress += 1
+		} else if task.TaskStatus == Quarantined {
+			quarantinedTasks += 1
+		}
+	}
+
+	return Progress{SamplesToDo: sr.samplesToDo, MapsToDo: sr.mapsToDo, ReducesToDo: sr.reducesToDo, TasksInProgress: tasksInProgress,
+		ReclaimedTasks: sr.reclaimedTasks, QuarantinedTasks: quarantinedTasks, SpeculativeTasks: sr.speculativeTasks}
+}
+
+func (sr *SharedResources) GetQuarantinedTasks() []QuarantinedTask {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	var quarantined []QuarantinedTask
+	for workName, task := range sr.tasksMap {
+		if task.TaskStatus == Quarantined {
+			quarantined = append(quarantined, QuarantinedTask{WorkName: workName, Task: task})
+		}
+	}
+
+	sort.Slice(quarantined, func(i, j int) bool {
+		return quarantined[i].WorkName < quarantined[j].WorkName
+	})
+
+	return quarantined
 }
 
 func (sr *SharedResources) IsAllWorkCompleted() bool {
 	sr.mutex.Lock()
 	defer sr.mutex.Unlock()
 
-	return sr.mapsToDo == 0 && sr.reducesToDo == 0
+	return sr.samplesToDo == 0 && sr.mapsToDo == 0 && sr.reducesToDo == 0
 }
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// InputSplit es un rango de bytes de un archivo de entrada. Length 0 significa hasta el final del archivo. Los
+// límites no tienen por qué caer en un salto de línea: el worker se queda con las líneas que empiezan dentro del
+// rango.
+type InputSplit struct {
+	Path   string
+	Offset int64
+	Length int64
+}
+
+func (s InputSplit) String() string {
+	if s.Offset == 0 && s.Length == 0 {
+		return s.Path
+	}
+	return fmt.Sprintf("%s[%d:%d]", s.Path, s.Offset, s.Offset+s.Length)
+}
+
+func DescribeSplits(splits []InputSplit) string {
+	descriptions := make([]string, len(splits))
+	for i, split := range splits {
+		descriptions[i] = split.String()
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// SplitInputs reparte los archivos de entrada en las entradas de las tareas map: los archivos más grandes que
+// splitSize se cortan en rangos de a lo sumo splitSize bytes y los más chicos se agrupan en una misma tarea hasta
+// juntar splitSize bytes. Con splitSize 0 cada archivo es una tarea, como siempre. Los archivos que no se pueden
+// leer quedan como una tarea propia, para que falle el map que los lee.
+func SplitInputs(inputFiles []string, splitSize int64) [][]InputSplit {
+	var tasks [][]InputSplit
+	var group []InputSplit
+	var groupSize int64
+
+	flushGroup := func() {
+		if len(group) > 0 {
+			tasks = append(tasks, group)
+			group, groupSize = nil, 0
+		}
+	}
+
+	for _, inputFile := range inputFiles {
+		info, err := os.Stat(inputFile)
+		if splitSize <= 0 || err != nil || info.IsDir() {
+			flushGroup()
+			tasks = append(tasks, []InputSplit{{Path: inputFile}})
+			continue
+		}
+
+		size := info.Size()
+		if size > splitSize {
+			flushGroup()
+			for offset := int64(0); offset < size; offset += splitSize {
+				tasks = append(tasks, []InputSplit{{Path: inputFile, Offset: offset, Length: min(splitSize, size-offset)}})
+			}
+			continue
+		}
+
+		if groupSize+size > splitSize {
+			flushGroup()
+		}
+		group = append(group, InputSplit{Path: inputFile})
+		groupSize += size
+	}
+	flushGroup()
+
+	return tasks
+}
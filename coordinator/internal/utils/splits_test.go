@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitInputs(t *testing.T) {
+	dir := t.TempDir()
+	sizes := map[string]int{"a": 3, "b": 3, "c": 4, "d": 10, "grande": 25, "vacio": 0}
+	for name, size := range sizes {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+	whole := func(name string) InputSplit { return InputSplit{Path: path(name)} }
+
+	tests := []struct {
+		name      string
+		inputs    []string
+		splitSize int64
+		want      [][]InputSplit
+	}{
+		{
+			name:   "sin split size cada archivo es una tarea",
+			inputs: []string{"a", "b", "grande"}, splitSize: 0,
+			want: [][]InputSplit{{whole("a")}, {whole("b")}, {whole("grande")}},
+		},
+		{
+			name:   "los archivos chicos se agrupan",
+			inputs: []string{"a", "b", "c"}, splitSize: 10,
+			want: [][]InputSplit{{whole("a"), whole("b"), whole("c")}},
+		},
+		{
+			name:   "un grupo se cierra cuando el siguiente archivo no entra",
+			inputs: []string{"a", "c", "b"}, splitSize: 8,
+			want: [][]InputSplit{{whole("a"), whole("c")}, {whole("b")}},
+		},
+		{
+			name:   "un archivo del tamaño justo no se corta",
+			inputs: []string{"d", "a"}, splitSize: 10,
+			want: [][]InputSplit{{whole("d")}, {whole("a")}},
+		},
+		{
+			name:   "los archivos grandes se cortan en rangos",
+			inputs: []string{"grande"}, splitSize: 10,
+			want: [][]InputSplit{
+				{{Path: path("grande"), Offset: 0, Length: 10}},
+				{{Path: path("grande"), Offset: 10, Length: 10}},
+				{{Path: path("grande"), Offset: 20, Length: 5}},
+			},
+		},
+		{
+			name:   "un archivo grande cierra el grupo anterior",
+			inputs: []string{"a", "grande", "b", "vacio"}, splitSize: 12,
+			want: [][]InputSplit{
+				{whole("a")},
+				{{Path: path("grande"), Offset: 0, Length: 12}},
+				{{Path: path("grande"), Offset: 12, Length: 12}},
+				{{Path: path("grande"), Offset: 24, Length: 1}},
+				{whole("b"), whole("vacio")},
+			},
+		},
+		{
+			name:   "los archivos que no se pueden leer quedan como tarea propia",
+			inputs: []string{"a", "no-existe", "b"}, splitSize: 10,
+			want: [][]InputSplit{{whole("a")}, {whole("no-existe")}, {whole("b")}},
+		},
+		{
+			name:   "un directorio queda como tarea propia",
+			inputs: []string{"a", "."}, splitSize: 10,
+			want: [][]InputSplit{{whole("a")}, {whole(".")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := make([]string, len(tt.inputs))
+			for i, input := range tt.inputs {
+				inputs[i] = path(input)
+			}
+
+			got := SplitInputs(inputs, tt.splitSize)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitInputs(%v, %d) =\n%v\nse esperaba\n%v", tt.inputs, tt.splitSize, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputSplitString(t *testing.T) {
+	splits := []InputSplit{{Path: "a.txt"}, {Path: "b.txt", Offset: 10, Length: 5}}
+	if got, want := DescribeSplits(splits), "a.txt, b.txt[10:15]"; got != want {
+		t.Errorf("DescribeSplits = %q, se esperaba %q", got, want)
+	}
+}
@@ -3,3 +3,8 @@ package utils
 const NotAssigned = "NotAssigned"
 const Assigned = "Assigned"
 const Finished = "Finished"
+const Quarantined = "Quarantined"
+
+const JobRunning = "Running"
+const JobCompleted = "Completed"
+const JobFailed = "Failed"
@@ -1,4 +1,5 @@
 package utils
 
+const Sample = "Sample"
 const Map = "Map"
 const Reduce = "Reduce"
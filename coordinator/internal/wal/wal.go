@@ -0,0 +1,162 @@
+package wal
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const Header = "Header"
+const Assign = "Assign"
+const Finish = "Finish"
+const Reclaim = "Reclaim"
+const Fail = "Fail"
+const Quarantine = "Quarantine"
+const Reopen = "Reopen"
+
+type Entry struct {
+	Operation      string   `json:"operation"`
+	WorkName       string   `json:"workName,omitempty"`
+	WorkerUuid     string   `json:"workerUuid,omitempty"`
+	WorkerIdentity string   `json:"workerIdentity,omitempty"`
+	Attempt        uint32   `json:"attempt,omitempty"`
+	ErrorMessage   string   `json:"errorMessage,omitempty"`
+	SampleKeys     []string `json:"sampleKeys,omitempty"`
+	Location       string   `json:"location,omitempty"`
+	Fingerprint    string   `json:"fingerprint,omitempty"`
+}
+
+type Log struct {
+	mutex sync.Mutex
+	path  string
+	file  *os.File
+}
+
+func Fingerprint(jobArguments ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(jobArguments, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+// Open abre el log de path y devuelve las transiciones que quedaron registradas para el job identificado por
+// fingerprint. Si el log no existe o pertenece a otro job, se descarta y se empieza uno nuevo.
+func Open(path string, fingerprint string) (*Log, []Entry, error) {
+	if path == "" {
+		return nil, nil, nil
+	}
+
+	entries, validSize, err := readEntries(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(entries) > 0 && entries[0].Operation == Header && entries[0].Fingerprint == fingerprint {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening state log %s: %v", path, err)
+		}
+		if err := file.Truncate(validSize); err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("error truncating state log %s: %v", path, err)
+		}
+		return &Log{path: path, file: file}, entries[1:], nil
+	}
+
+	if len(entries) > 0 {
+		log.Printf("State log %s belongs to another job, starting from scratch", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, nil, fmt.Errorf("error creating state log directory: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating state log %s: %v", path, err)
+	}
+
+	stateLog := &Log{path: path, file: file}
+	if err := stateLog.Append(Entry{Operation: Header, Fingerprint: fingerprint}); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return stateLog, nil, nil
+}
+
+func (l *Log) Append(entry Entry) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding state log entry: %v", err)
+	}
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("error writing state log %s: %v", l.path, err)
+	}
+
+	return l.file.Sync()
+}
+
+func (l *Log) Remove() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.file.Close()
+	return os.Remove(l.path)
+}
+
+func readEntries(path string) ([]Entry, int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("error opening state log %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	var validSize int64
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				// Una entrada sin salto de línea final quedó a medio escribir cuando el coordinator murió.
+				log.Printf("Ignoring torn entry at the end of state log %s", path)
+			}
+			return entries, validSize, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("error reading state log %s: %v", path, err)
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			log.Printf("Ignoring corrupt entry in state log %s: %v", path, err)
+			return entries, validSize, nil
+		}
+		entries = append(entries, entry)
+		validSize += int64(len(line))
+	}
+}
@@ -0,0 +1,35 @@
+package inputs
+
+import (
+	"flag"
+	"strings"
+)
+
+// patternList es un flag que se puede repetir o recibir varios patrones separados por comas.
+type patternList struct {
+	patterns *[]string
+}
+
+func (p patternList) String() string {
+	if p.patterns == nil {
+		return ""
+	}
+	return strings.Join(*p.patterns, ",")
+}
+
+func (p patternList) Set(value string) error {
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern != "" {
+			*p.patterns = append(*p.patterns, pattern)
+		}
+	}
+	return nil
+}
+
+// RegisterFilterFlags agrega -include y -exclude al FlagSet.
+func RegisterFilterFlags(flags *flag.FlagSet) *Filters {
+	filters := &Filters{}
+	flags.Var(patternList{patterns: &filters.Include}, "include", "procesar solo los archivos que coinciden con alguno de estos patrones (se puede repetir o separar con comas)")
+	flags.Var(patternList{patterns: &filters.Exclude}, "exclude", "descartar los archivos que coinciden con alguno de estos patrones (se puede repetir o separar con comas)")
+	return filters
+}
@@ -0,0 +1,161 @@
+package inputs
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Filters restringe los archivos resueltos. Cada patrón (sintaxis de filepath.Match) se compara contra la ruta
+// completa y contra el nombre del archivo. Si hay patrones Include solo quedan los archivos que coinciden con
+// alguno; los que coinciden con algún Exclude se descartan siempre.
+type Filters struct {
+	Include []string
+	Exclude []string
+}
+
+// Resolve traduce las entradas de un job a la lista de archivos que hay que procesar, en orden y sin repetidos:
+//   - "@archivo" es un manifiesto con una entrada por línea (se ignoran las líneas vacías y las que empiezan con #),
+//     que a su vez puede ser cualquiera de estas formas;
+//   - un directorio se recorre recursivamente, salteando los archivos y directorios ocultos;
+//   - un patrón con *, ? o [ se expande con filepath.Glob y tiene que coincidir con algo;
+//   - cualquier otra cosa se toma como la ruta de un archivo, exista o no (si no existe fallará el map que lo lea).
+func Resolve(entries []string, filters Filters) ([]string, error) {
+	if err := filters.validate(); err != nil {
+		return nil, err
+	}
+
+	resolver := &resolver{filters: filters, seen: make(map[string]bool)}
+	for _, entry := range entries {
+		if err := resolver.resolve(entry, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolver.files, nil
+}
+
+type resolver struct {
+	filters Filters
+	files   []string
+	seen    map[string]bool
+}
+
+func (r *resolver) resolve(entry string, manifests []string) error {
+	if manifest, isManifest := strings.CutPrefix(entry, "@"); isManifest {
+		return r.resolveManifest(manifest, manifests)
+	}
+
+	if strings.ContainsAny(entry, "*?[") {
+		matches, err := filepath.Glob(entry)
+		if err != nil {
+			return fmt.Errorf("patrón inválido %q: %v", entry, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("el patrón %q no coincide con ningún archivo", entry)
+		}
+		for _, match := range matches {
+			if err := r.resolvePath(match); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return r.resolvePath(entry)
+}
+
+func (r *resolver) resolveManifest(manifest string, manifests []string) error {
+	for _, opened := range manifests {
+		if opened == manifest {
+			return fmt.Errorf("el manifiesto %s se incluye a sí mismo", manifest)
+		}
+	}
+
+	file, err := os.Open(manifest)
+	if err != nil {
+		return fmt.Errorf("error abriendo el manifiesto %s: %v", manifest, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := r.resolve(line, append(manifests, manifest)); err != nil {
+			return fmt.Errorf("%s: %v", manifest, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error leyendo el manifiesto %s: %v", manifest, err)
+	}
+	return nil
+}
+
+func (r *resolver) resolvePath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		r.add(path)
+		return nil
+	}
+
+	return filepath.WalkDir(path, func(walked string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error recorriendo %s: %v", walked, err)
+		}
+		if walked != path && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			r.add(walked)
+		}
+		return nil
+	})
+}
+
+func (r *resolver) add(path string) {
+	path = filepath.Clean(path)
+	if r.seen[path] || !r.filters.accepts(path) {
+		return
+	}
+
+	r.seen[path] = true
+	r.files = append(r.files, path)
+}
+
+func (f Filters) validate() error {
+	for _, pattern := range append(append([]string(nil), f.Include...), f.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("patrón inválido %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (f Filters) accepts(path string) bool {
+	if len(f.Include) > 0 && !matchesAny(f.Include, path) {
+		return false
+	}
+	return !matchesAny(f.Exclude, path)
+}
+
+func matchesAny(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package intermediate
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CodecNone deja los pares sin comprimir.
+const CodecNone = "none"
+
+const DefaultCodec = CodecNone
+
+// Codec comprime el cuerpo de un archivo intermedio (todo lo que sigue al encabezado).
+type Codec struct {
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+	NewReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	codecsMutex sync.RWMutex
+	codecs      = map[string]Codec{
+		"gzip": {
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		},
+		"zlib": {
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) { return zlib.NewWriter(w), nil },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) { return zlib.NewReader(r) },
+		},
+		"flate": {
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) { return flate.NewWriter(w, flate.DefaultCompression) },
+			NewReader: func(r io.Reader) (io.ReadCloser, error) { return flate.NewReader(r), nil },
+		},
+	}
+)
+
+// RegisterCodec agrega un codec con el nombre con el que se lo elige por job y se lo registra en el encabezado.
+func RegisterCodec(name string, codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+
+	codecs[name] = codec
+}
+
+func lookupCodec(name string) (Codec, error) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+
+	codec, exists := codecs[name]
+	if !exists {
+		return Codec{}, fmt.Errorf("codec intermedio desconocido %q (se admite %s)", name, strings.Join(codecNames(), ", "))
+	}
+	return codec, nil
+}
+
+func codecNames() []string {
+	names := []string{CodecNone}
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// Encoding es la combinación de formato y codec con la que se escriben los archivos intermedios de un job.
+type Encoding struct {
+	Format string
+	Codec  string
+}
+
+// Validate verifica que el formato y el codec existan y se puedan combinar: el formato text no lleva encabezado, así
+// que no tiene dónde registrar el codec.
+func (e Encoding) Validate() error {
+	if err := ValidateFormat(e.Format); err != nil {
+		return err
+	}
+	if e.Codec == CodecNone {
+		return nil
+	}
+	if _, err := lookupCodec(e.Codec); err != nil {
+		return err
+	}
+	if e.Format == FormatText {
+		return fmt.Errorf("el formato %s no admite compresión", FormatText)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package intermediate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"tp1/mr"
+)
+
+var compressedFormats = []string{FormatJSONL, FormatBinary}
+
+func manyPairs(n int) []mr.KeyValue {
+	kvs := make([]mr.KeyValue, n)
+	for i := range kvs {
+		kvs[i] = mr.KeyValue{Key: fmt.Sprintf("clave %d", i), Value: strings.Repeat("v", i%7)}
+	}
+	return kvs
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	pairs := append(manyPairs(500), awkwardPairs...)
+
+	for _, format := range compressedFormats {
+		for _, codec := range codecNames() {
+			t.Run(format+"/"+codec, func(t *testing.T) {
+				data := encode(t, Encoding{Format: format, Codec: codec}, pairs)
+
+				wantHeader := header{format: format, codec: codec}.String()
+				if !bytes.HasPrefix(data, []byte(wantHeader)) {
+					t.Errorf("el archivo no empieza con el encabezado %q", wantHeader)
+				}
+
+				got, err := decode(data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, pairs) {
+					t.Errorf("se leyeron %d pares distintos de los %d escritos", len(got), len(pairs))
+				}
+			})
+		}
+	}
+}
+
+func TestCodecReportsTruncatedData(t *testing.T) {
+	for _, format := range compressedFormats {
+		for _, codec := range codecNames() {
+			if codec == CodecNone {
+				continue
+			}
+			t.Run(format+"/"+codec, func(t *testing.T) {
+				data := encode(t, Encoding{Format: format, Codec: codec}, manyPairs(500))
+
+				got, err := decode(data[:len(data)/2])
+				if err == nil {
+					t.Fatalf("se esperaba un error al leer el archivo cortado, se leyeron %d pares", len(got))
+				}
+			})
+		}
+	}
+}
+
+func TestCodecReportsCorruptData(t *testing.T) {
+	// gzip y zlib verifican un checksum, así que cualquier byte alterado se detecta
+	for _, codec := range []string{"gzip", "zlib"} {
+		t.Run(codec, func(t *testing.T) {
+			data := encode(t, Encoding{Format: FormatJSONL, Codec: codec}, manyPairs(500))
+
+			headerLength := len(header{format: FormatJSONL, codec: codec}.String())
+			corrupt := append([]byte{}, data...)
+			for i := headerLength + (len(data)-headerLength)/2; i < len(corrupt)-8; i += 16 {
+				corrupt[i] ^= 0xff
+			}
+
+			if _, err := decode(corrupt); err == nil {
+				t.Error("se esperaba un error al leer el archivo corrupto")
+			}
+		})
+	}
+}
+
+func TestEncodingValidate(t *testing.T) {
+	tests := []struct {
+		encoding Encoding
+		valid    bool
+	}{
+		{encoding: Encoding{Format: FormatText, Codec: CodecNone}, valid: true},
+		{encoding: Encoding{Format: FormatJSONL, Codec: "gzip"}, valid: true},
+		{encoding: Encoding{Format: FormatBinary, Codec: "flate"}, valid: true},
+		{encoding: Encoding{Format: FormatText, Codec: "gzip"}},
+		{encoding: Encoding{Format: FormatJSONL, Codec: "lz4"}},
+		{encoding: Encoding{Format: "csv", Codec: CodecNone}},
+	}
+
+	for _, tt := range tests {
+		err := tt.encoding.Validate()
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, se esperaba válido = %v", tt.encoding, err, tt.valid)
+		}
+	}
+}
+
+func TestReaderRejectsUnknownCodec(t *testing.T) {
+	data := "mr-intermediate/1 format=jsonl codec=lz4\n"
+	if _, err := NewReader(strings.NewReader(data)); err == nil {
+		t.Error("se esperaba un error con un codec desconocido")
+	}
+}
+
+type closeTrackingWriter struct {
+	io.Writer
+	closed *bool
+}
+
+func (w closeTrackingWriter) Close() error {
+	*w.closed = true
+	return nil
+}
+
+func TestRegisterCodec(t *testing.T) {
+	closed := false
+	RegisterCodec("identidad", Codec{
+		NewWriter: func(w io.Writer) (io.WriteCloser, error) { return closeTrackingWriter{Writer: w, closed: &closed}, nil },
+		NewReader: func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(r), nil },
+	})
+	defer func() {
+		codecsMutex.Lock()
+		delete(codecs, "identidad")
+		codecsMutex.Unlock()
+	}()
+
+	data := encode(t, Encoding{Format: FormatJSONL, Codec: "identidad"}, plainPairs)
+	if !closed {
+		t.Error("Close del Writer no cerró el compresor")
+	}
+
+	got, err := decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, plainPairs) {
+		t.Errorf("se leyó %q, se esperaba %q", got, plainPairs)
+	}
+}
@@ -0,0 +1,80 @@
+package intermediate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formatos de los archivos intermedios que escriben los maps y leen los reduces.
+const (
+	// FormatText es el formato original "clave valor" por línea, sin encabezado. No admite claves con espacios ni
+	// saltos de línea en claves o valores.
+	FormatText = "text"
+	// FormatJSONL escribe cada par como un objeto JSON por línea. Requiere claves y valores UTF-8 válidos.
+	FormatJSONL = "jsonl"
+	// FormatBinary escribe cada par con la longitud de la clave y del valor por delante, así cualquier secuencia de
+	// bytes vuelve tal cual.
+	FormatBinary = "binary"
+)
+
+const DefaultFormat = FormatJSONL
+
+// Version es la versión del encabezado con el que empiezan los archivos en los formatos nuevos, por ejemplo
+// "mr-intermediate/1 format=jsonl codec=gzip".
+const Version = 1
+
+const headerPrefix = "mr-intermediate/"
+
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSONL, FormatBinary:
+		return nil
+	default:
+		return fmt.Errorf("formato intermedio desconocido %q (se admite %s, %s o %s)", format, FormatText, FormatJSONL, FormatBinary)
+	}
+}
+
+type header struct {
+	format string
+	codec  string
+}
+
+func (h header) String() string {
+	return fmt.Sprintf("%s%d format=%s codec=%s\n", headerPrefix, Version, h.format, h.codec)
+}
+
+func parseHeader(line string) (header, error) {
+	fields := strings.Fields(line)
+
+	version, err := strconv.Atoi(strings.TrimPrefix(fields[0], headerPrefix))
+	if err != nil || version != Version {
+		return header{}, fmt.Errorf("versión de archivo intermedio no soportada: %q", fields[0])
+	}
+
+	parsed := header{codec: CodecNone}
+	for _, field := range fields[1:] {
+		name, value, found := strings.Cut(field, "=")
+		if !found {
+			return header{}, fmt.Errorf("campo inválido en el encabezado: %q", field)
+		}
+
+		switch name {
+		case "format":
+			parsed.format = value
+		case "codec":
+			parsed.codec = value
+		default:
+			return header{}, fmt.Errorf("campo desconocido en el encabezado: %q", name)
+		}
+	}
+
+	if parsed.format == FormatText {
+		return header{}, fmt.Errorf("el formato %s no lleva encabezado", FormatText)
+	}
+	if err := (Encoding{Format: parsed.format, Codec: parsed.codec}).Validate(); err != nil {
+		return header{}, err
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,186 @@
+package intermediate
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"tp1/mr"
+)
+
+func encode(t *testing.T, encoding Encoding, kvs []mr.KeyValue) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer, err := NewWriter(&buffer, encoding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kv := range kvs {
+		if err := writer.Write(kv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+// decode lee todos los pares y devuelve el primer error distinto de io.EOF.
+func decode(data []byte) ([]mr.KeyValue, error) {
+	reader, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var kvs []mr.KeyValue
+	for {
+		kv, err := reader.Next()
+		if err == io.EOF {
+			return kvs, nil
+		}
+		if err != nil {
+			return kvs, err
+		}
+		kvs = append(kvs, kv)
+	}
+}
+
+var plainPairs = []mr.KeyValue{{Key: "hola", Value: "1"}, {Key: "mundo", Value: "2"}, {Key: "hola", Value: "1"}}
+
+// awkwardPairs tiene lo que el formato text no puede representar: espacios y saltos de línea en claves y valores,
+// valores vacíos y caracteres no ASCII.
+var awkwardPairs = []mr.KeyValue{
+	{Key: "clave con espacios", Value: "valor con espacios"},
+	{Key: "multi\nlínea", Value: "a\nb\n"},
+	{Key: "vacío", Value: ""},
+	{Key: "", Value: "clave vacía"},
+	{Key: "ñandú", Value: "{\"json\": true}"},
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		pairs  []mr.KeyValue
+	}{
+		{name: "text", format: FormatText, pairs: plainPairs},
+		{name: "jsonl", format: FormatJSONL, pairs: append(append([]mr.KeyValue{}, plainPairs...), awkwardPairs...)},
+		{name: "binary", format: FormatBinary, pairs: append(append([]mr.KeyValue{}, plainPairs...), awkwardPairs...)},
+		{name: "binary sin UTF-8", format: FormatBinary, pairs: []mr.KeyValue{{Key: "\xff\xfe", Value: "\x00\x01"}}},
+		{name: "jsonl vacío", format: FormatJSONL},
+		{name: "binary vacío", format: FormatBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := encode(t, Encoding{Format: tt.format, Codec: CodecNone}, tt.pairs)
+
+			got, err := decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.pairs) || (len(got) > 0 && !reflect.DeepEqual(got, tt.pairs)) {
+				t.Errorf("se leyó %q, se esperaba %q", got, tt.pairs)
+			}
+		})
+	}
+}
+
+func TestReaderDetectsFormat(t *testing.T) {
+	for _, format := range []string{FormatText, FormatJSONL, FormatBinary} {
+		reader, err := NewReader(bytes.NewReader(encode(t, Encoding{Format: format, Codec: CodecNone}, plainPairs)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reader.Format() != format {
+			t.Errorf("se detectó el formato %s, se esperaba %s", reader.Format(), format)
+		}
+	}
+}
+
+func TestWriterRejectsUnrepresentablePairs(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		kv     mr.KeyValue
+	}{
+		{name: "text con espacio en la clave", format: FormatText, kv: mr.KeyValue{Key: "a b", Value: "1"}},
+		{name: "text con salto de línea en la clave", format: FormatText, kv: mr.KeyValue{Key: "a\nb", Value: "1"}},
+		{name: "text con salto de línea en el valor", format: FormatText, kv: mr.KeyValue{Key: "a", Value: "1\n2"}},
+		{name: "jsonl sin UTF-8", format: FormatJSONL, kv: mr.KeyValue{Key: "\xff", Value: "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer, err := NewWriter(io.Discard, Encoding{Format: tt.format, Codec: CodecNone})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := writer.Write(tt.kv); err == nil {
+				t.Errorf("se esperaba un error al escribir %q en formato %s", tt.kv, tt.format)
+			}
+		})
+	}
+}
+
+func TestReaderReportsMalformedRecords(t *testing.T) {
+	jsonl := encode(t, Encoding{Format: FormatJSONL, Codec: CodecNone}, plainPairs)
+	binary := encode(t, Encoding{Format: FormatBinary, Codec: CodecNone}, plainPairs)
+	header := header{format: FormatBinary, codec: CodecNone}.String()
+
+	tests := []struct {
+		name      string
+		data      []byte
+		wantPairs int
+		wantError string
+	}{
+		{name: "text sin separador", data: []byte("hola 1\nmundo\n"), wantPairs: 1, wantError: "mal formado"},
+		{name: "jsonl con una línea corrupta", data: append(append([]byte{}, jsonl...), "{\"Key\": \"a\"\n"...),
+			wantPairs: len(plainPairs), wantError: "mal formado"},
+		{name: "jsonl cortado a mitad de registro", data: jsonl[:len(jsonl)-4], wantPairs: len(plainPairs) - 1,
+			wantError: "mal formado"},
+		{name: "binary sin el valor del último registro", data: binary[:len(binary)-2], wantPairs: len(plainPairs) - 1,
+			wantError: "truncado"},
+		{name: "binary cortado en la longitud", data: append([]byte(header), 0x80), wantError: "truncado"},
+		{name: "binary con longitud fuera de rango", data: append([]byte(header), 0xff, 0xff, 0xff, 0xff, 0x0f),
+			wantError: "fuera de rango"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decode(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Fatalf("se esperaba un error %q, se obtuvo %v", tt.wantError, err)
+			}
+			if len(got) != tt.wantPairs {
+				t.Errorf("se leyeron %d pares antes del error, se esperaban %d", len(got), tt.wantPairs)
+			}
+		})
+	}
+}
+
+func TestReaderRejectsInvalidHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "versión desconocida", header: "mr-intermediate/2 format=jsonl codec=none\n"},
+		{name: "formato desconocido", header: "mr-intermediate/1 format=csv codec=none\n"},
+		{name: "formato text con encabezado", header: "mr-intermediate/1 format=text codec=none\n"},
+		{name: "campo desconocido", header: "mr-intermediate/1 format=jsonl codec=none extra=1\n"},
+		{name: "encabezado incompleto", header: "mr-intermediate/1 format=jsonl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReader(strings.NewReader(tt.header)); err == nil {
+				t.Errorf("se esperaba un error con el encabezado %q", tt.header)
+			}
+		})
+	}
+}
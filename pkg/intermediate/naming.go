@@ -0,0 +1,10 @@
+package intermediate
+
+import "fmt"
+
+// MapOutputName es el nombre del archivo con la partición partition que escribe el intento attempt del map
+// mapTaskId. Como incluye el intento, dos intentos de la misma tarea nunca comparten archivo y el coordinator puede
+// indicarle a cada reduce exactamente qué archivos leer a partir del intento que registró como terminado.
+func MapOutputName(mapTaskId int, attempt int, partition int) string {
+	return fmt.Sprintf("mr-%d-%d-%d", mapTaskId, attempt, partition)
+}
@@ -0,0 +1,156 @@
+package intermediate
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"tp1/mr"
+)
+
+// maxRecordFieldSize acota la longitud de una clave o valor en formato binary, para no reservar memoria de más
+// si el archivo está corrupto.
+const maxRecordFieldSize = 1 << 30
+
+// Reader lee los pares de un archivo intermedio. El formato se detecta por el encabezado: los archivos sin
+// encabezado se leen en el formato text original. Un registro mal formado se reporta como error en vez de
+// descartarse.
+type Reader struct {
+	reader       *bufio.Reader
+	decompressor io.ReadCloser
+	format       string
+	record       int
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{reader: bufio.NewReaderSize(r, 64*1024), format: FormatText}
+
+	prefix, err := reader.reader.Peek(len(headerPrefix))
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if string(prefix) != headerPrefix {
+		return reader, nil
+	}
+
+	line, err := reader.reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("encabezado incompleto: %v", err)
+	}
+
+	parsed, err := parseHeader(strings.TrimSuffix(line, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	reader.format = parsed.format
+
+	if parsed.codec != CodecNone {
+		registered, err := lookupCodec(parsed.codec)
+		if err != nil {
+			return nil, err
+		}
+		if reader.decompressor, err = registered.NewReader(reader.reader); err != nil {
+			return nil, fmt.Errorf("error descomprimiendo (%s): %v", parsed.codec, err)
+		}
+		reader.reader = bufio.NewReaderSize(reader.decompressor, 64*1024)
+	}
+
+	return reader, nil
+}
+
+// Close libera el descompresor; el archivo de abajo no se cierra.
+func (r *Reader) Close() error {
+	if r.decompressor != nil {
+		return r.decompressor.Close()
+	}
+	return nil
+}
+
+func (r *Reader) Format() string {
+	return r.format
+}
+
+// Next devuelve el siguiente par, o io.EOF cuando se termina el archivo.
+func (r *Reader) Next() (mr.KeyValue, error) {
+	r.record += 1
+
+	switch r.format {
+	case FormatJSONL:
+		line, err := r.readLine()
+		if err != nil {
+			return mr.KeyValue{}, err
+		}
+		var kv mr.KeyValue
+		if err := json.Unmarshal([]byte(line), &kv); err != nil {
+			return mr.KeyValue{}, fmt.Errorf("registro %d mal formado: %v", r.record, err)
+		}
+		return kv, nil
+
+	case FormatBinary:
+		key, err := r.readBytes()
+		if err == io.EOF {
+			return mr.KeyValue{}, io.EOF
+		}
+		if err != nil {
+			return mr.KeyValue{}, fmt.Errorf("registro %d truncado: %v", r.record, err)
+		}
+		value, err := r.readBytes()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return mr.KeyValue{}, fmt.Errorf("registro %d truncado: %v", r.record, err)
+		}
+		return mr.KeyValue{Key: key, Value: value}, nil
+
+	default:
+		line, err := r.readLine()
+		if err != nil {
+			return mr.KeyValue{}, err
+		}
+		key, value, found := strings.Cut(line, " ")
+		if !found {
+			return mr.KeyValue{}, fmt.Errorf("registro %d mal formado: %q no tiene el formato \"clave valor\"", r.record, line)
+		}
+		return mr.KeyValue{Key: key, Value: value}, nil
+	}
+}
+
+// readLine devuelve la siguiente línea no vacía, sin el salto de línea.
+func (r *Reader) readLine() (string, error) {
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line != "" {
+			return line, nil
+		}
+	}
+}
+
+func (r *Reader) readBytes() (string, error) {
+	length, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		return "", err
+	}
+
+	if length > maxRecordFieldSize {
+		return "", fmt.Errorf("longitud %d fuera de rango", length)
+	}
+
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r.reader, value); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+
+	return string(value), nil
+}
@@ -0,0 +1,101 @@
+package intermediate
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"tp1/mr"
+	"unicode/utf8"
+)
+
+// Writer escribe pares en uno de los formatos intermedios. Hay que llamar a Close al terminar para vaciar los
+// buffers y cerrar el compresor; el archivo de abajo no se cierra.
+type Writer struct {
+	writer     *bufio.Writer
+	compressor io.WriteCloser
+	format     string
+}
+
+// NewWriter escribe el encabezado (salvo en el formato text) sin comprimir y devuelve el Writer para los pares, que
+// se comprimen con el codec del encoding.
+func NewWriter(w io.Writer, encoding Encoding) (*Writer, error) {
+	if err := encoding.Validate(); err != nil {
+		return nil, err
+	}
+
+	if encoding.Format != FormatText {
+		if _, err := io.WriteString(w, header{format: encoding.Format, codec: encoding.Codec}.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	writer := &Writer{format: encoding.Format}
+	if encoding.Codec != CodecNone {
+		registered, err := lookupCodec(encoding.Codec)
+		if err != nil {
+			return nil, err
+		}
+		if writer.compressor, err = registered.NewWriter(w); err != nil {
+			return nil, err
+		}
+		w = writer.compressor
+	}
+	writer.writer = bufio.NewWriter(w)
+
+	return writer, nil
+}
+
+func (w *Writer) Write(kv mr.KeyValue) error {
+	switch w.format {
+	case FormatJSONL:
+		if !utf8.ValidString(kv.Key) || !utf8.ValidString(kv.Value) {
+			return fmt.Errorf("el par con clave %q no es UTF-8 válido, no se puede escribir en formato %s (usar %s)",
+				kv.Key, FormatJSONL, FormatBinary)
+		}
+		line, err := json.Marshal(kv)
+		if err != nil {
+			return err
+		}
+		if _, err := w.writer.Write(line); err != nil {
+			return err
+		}
+		return w.writer.WriteByte('\n')
+
+	case FormatBinary:
+		if err := w.writeBytes(kv.Key); err != nil {
+			return err
+		}
+		return w.writeBytes(kv.Value)
+
+	default:
+		if strings.ContainsAny(kv.Key, " \n") || strings.Contains(kv.Value, "\n") {
+			return fmt.Errorf("el par con clave %q no se puede escribir en formato %s (usar %s o %s)",
+				kv.Key, FormatText, FormatJSONL, FormatBinary)
+		}
+		_, err := fmt.Fprintf(w.writer, "%s %s\n", kv.Key, kv.Value)
+		return err
+	}
+}
+
+func (w *Writer) writeBytes(value string) error {
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(value)))
+	if _, err := w.writer.Write(length[:n]); err != nil {
+		return err
+	}
+	_, err := w.writer.WriteString(value)
+	return err
+}
+
+func (w *Writer) Close() error {
+	if err := w.writer.Flush(); err != nil {
+		return err
+	}
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"net"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const AddressEnv = "MR_COORDINATOR_ADDR"
+const unixScheme = "unix://"
+
+// DefaultAddress devuelve la dirección del coordinator configurada en el entorno o, si no hay ninguna, el socket
+// Unix local de siempre.
+func DefaultAddress() string {
+	if address := os.Getenv(AddressEnv); address != "" {
+		return address
+	}
+	return unixScheme + "/tmp/mr-socket.sock"
+}
+
+// ParseAddress traduce una dirección unix:///ruta o host:port a la red y dirección que entiende net.Listen.
+func ParseAddress(address string) (string, string) {
+	if strings.HasPrefix(address, unixScheme) {
+		return "unix", strings.TrimPrefix(address, unixScheme)
+	}
+	return "tcp", address
+}
+
+func Listen(address string) (net.Listener, error) {
+	network, listenAddress := ParseAddress(address)
+
+	if network == "unix" {
+		os.Remove(listenAddress)
+	}
+
+	return net.Listen(network, listenAddress)
+}
+
+func Cleanup(address string) {
+	if network, listenAddress := ParseAddress(address); network == "unix" {
+		os.Remove(listenAddress)
+	}
+}
+
+// DialTarget devuelve el target de gRPC para la dirección: los sockets Unix mantienen su esquema y el resto se
+// resuelve por DNS.
+func DialTarget(address string) string {
+	if network, _ := ParseAddress(address); network == "unix" {
+		return address
+	}
+	return "dns:///" + address
+}
+
+func IsUnavailable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
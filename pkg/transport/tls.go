@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+)
+
+type TLSConfig struct {
+	CertFile   string
+	KeyFile    string
+	CAFile     string
+	ServerName string
+}
+
+func RegisterTLSFlags(flags *flag.FlagSet) *TLSConfig {
+	config := &TLSConfig{}
+	flags.StringVar(&config.CertFile, "tls-cert", "", "certificado propio para mTLS (vacío para no usar TLS)")
+	flags.StringVar(&config.KeyFile, "tls-key", "", "clave privada del certificado propio")
+	flags.StringVar(&config.CAFile, "tls-ca", "", "CA con la que se verifican los certificados de la otra punta")
+	return config
+}
+
+func RegisterTLSClientFlags(flags *flag.FlagSet) *TLSConfig {
+	config := RegisterTLSFlags(flags)
+	flags.StringVar(&config.ServerName, "tls-server-name", "", "nombre esperado en el certificado del coordinator (por defecto, el host de la dirección)")
+	return config
+}
+
+func (c *TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || c.CAFile != ""
+}
+
+func (c *TLSConfig) load() (tls.Certificate, *x509.CertPool, error) {
+	if c.CertFile == "" || c.KeyFile == "" || c.CAFile == "" {
+		return tls.Certificate{}, nil, fmt.Errorf("mTLS necesita -tls-cert, -tls-key y -tls-ca")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("error cargando certificado %s: %v", c.CertFile, err)
+	}
+
+	caPem, err := os.ReadFile(c.CAFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("error leyendo CA %s: %v", c.CAFile, err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPem) {
+		return tls.Certificate{}, nil, fmt.Errorf("la CA %s no contiene certificados válidos", c.CAFile)
+	}
+
+	return certificate, caPool, nil
+}
+
+func (c *TLSConfig) ServerOptions() ([]grpc.ServerOption, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+
+	certificate, caPool, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}))}, nil
+}
+
+func (c *TLSConfig) DialOption() (grpc.DialOption, error) {
+	if !c.Enabled() {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+
+	certificate, caPool, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      caPool,
+		ServerName:   c.ServerName,
+		MinVersion:   tls.VersionTLS12,
+	})), nil
+}
+
+// PeerIdentity devuelve la identidad autenticada por mTLS de quien hizo el pedido (el CN de su certificado o, si
+// no tiene, su primer nombre DNS). Sin TLS devuelve "".
+func PeerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	certificate := tlsInfo.State.VerifiedChains[0][0]
+	if certificate.Subject.CommonName != "" {
+		return certificate.Subject.CommonName
+	}
+	if len(certificate.DNSNames) > 0 {
+		return certificate.DNSNames[0]
+	}
+	return ""
+}
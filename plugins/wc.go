@@ -1,6 +1,7 @@
 package main
 
 import (
+    "fmt"
     "strings"
     "strconv"
     "tp1/mr" 
@@ -16,6 +17,25 @@ func Map(filename string, content string) []mr.KeyValue{
 	return wordCount
 }
 
+// Combine suma las apariciones de cada palabra en el map, así a los reduces llega un solo par por palabra y archivo
+func Combine(key string, values []string) []string {
+	return []string{strconv.Itoa(sum(values))}
+}
+
 func Reduce(key string, values []string) string {
-	return strconv.Itoa(len(values)) 
+	return strconv.Itoa(sum(values))
+}
+
+// sum suma los conteos. Un valor que no es un número indica datos intermedios corruptos: se hace fallar la tarea en
+// vez de contarlo como una aparición y devolver un resultado plausible pero incorrecto.
+func sum(values []string) int {
+	total := 0
+	for _, value := range values {
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			panic(fmt.Sprintf("conteo inválido %q: %v", value, err))
+		}
+		total += count
+	}
+	return total
 }
@@ -22,12 +22,16 @@ const (
 )
 
 type IFinished struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkerUuid    string                 `protobuf:"bytes,1,opt,name=workerUuid,proto3" json:"workerUuid,omitempty"`
-	WorkFinished  string                 `protobuf:"bytes,2,opt,name=workFinished,proto3" json:"workFinished,omitempty"`
-	WorkType      string                 `protobuf:"bytes,3,opt,name=workType,proto3" json:"workType,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	WorkerUuid     string                 `protobuf:"bytes,1,opt,name=workerUuid,proto3" json:"workerUuid,omitempty"`
+	WorkFinished   string                 `protobuf:"bytes,2,opt,name=workFinished,proto3" json:"workFinished,omitempty"`
+	WorkType       string                 `protobuf:"bytes,3,opt,name=workType,proto3" json:"workType,omitempty"`
+	JobId          string                 `protobuf:"bytes,4,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	Attempt        int32                  `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	SampleKeys     []string               `protobuf:"bytes,6,rep,name=sampleKeys,proto3" json:"sampleKeys,omitempty"`
+	ShuffleAddress string                 `protobuf:"bytes,7,opt,name=shuffleAddress,proto3" json:"shuffleAddress,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *IFinished) Reset() {
@@ -81,6 +85,34 @@ func (x *IFinished) GetWorkType() string {
 	return ""
 }
 
+func (x *IFinished) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *IFinished) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *IFinished) GetSampleKeys() []string {
+	if x != nil {
+		return x.SampleKeys
+	}
+	return nil
+}
+
+func (x *IFinished) GetShuffleAddress() string {
+	if x != nil {
+		return x.ShuffleAddress
+	}
+	return ""
+}
+
 type ImFree struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkerUuid    string                 `protobuf:"bytes,1,opt,name=workerUuid,proto3" json:"workerUuid,omitempty"`
@@ -125,17 +157,25 @@ func (x *ImFree) GetWorkerUuid() string {
 	return ""
 }
 
+// La configuración del job (reducers, plugin, formato, etc.) no viaja en cada asignación: el worker la pide una vez
+// con GetJobConfig
 type AskForWorkResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId      int32                  `protobuf:"varint,1,opt,name=workerId,proto3" json:"workerId,omitempty"`
-	ReducerNumber int32                  `protobuf:"varint,2,opt,name=reducerNumber,proto3" json:"reducerNumber,omitempty"`
-	WorkType      string                 `protobuf:"bytes,3,opt,name=workType,proto3" json:"workType,omitempty"`
-	FilePath      string                 `protobuf:"bytes,4,opt,name=filePath,proto3" json:"filePath,omitempty"`
-	Plugin        string                 `protobuf:"bytes,5,opt,name=plugin,proto3" json:"plugin,omitempty"`
-	Response      string                 `protobuf:"bytes,6,opt,name=response,proto3" json:"response,omitempty"`
-	MapNumber     int32                  `protobuf:"varint,7,opt,name=mapNumber,proto3" json:"mapNumber,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TaskId            int32                  `protobuf:"varint,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
+	WorkType          string                 `protobuf:"bytes,3,opt,name=workType,proto3" json:"workType,omitempty"`
+	FilePath          string                 `protobuf:"bytes,4,opt,name=filePath,proto3" json:"filePath,omitempty"`
+	Response          string                 `protobuf:"bytes,6,opt,name=response,proto3" json:"response,omitempty"`
+	JobId             string                 `protobuf:"bytes,8,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	Attempt           int32                  `protobuf:"varint,10,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	PartitionBounds   []string               `protobuf:"bytes,12,rep,name=partitionBounds,proto3" json:"partitionBounds,omitempty"`
+	InputSplits       []*InputSplit          `protobuf:"bytes,16,rep,name=inputSplits,proto3" json:"inputSplits,omitempty"`
+	MapOutputs        []*MapOutput           `protobuf:"bytes,17,rep,name=mapOutputs,proto3" json:"mapOutputs,omitempty"`
+	SkippedMapTaskIds []int32                `protobuf:"varint,18,rep,packed,name=skippedMapTaskIds,proto3" json:"skippedMapTaskIds,omitempty"`
+	JobGeneration     int32                  `protobuf:"varint,19,opt,name=jobGeneration,proto3" json:"jobGeneration,omitempty"`
+	// Direcciones de los workers que sirven las salidas de mapOutputs, cada una una sola vez
+	MapOutputLocations []string `protobuf:"bytes,20,rep,name=mapOutputLocations,proto3" json:"mapOutputLocations,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *AskForWorkResponse) Reset() {
@@ -168,16 +208,9 @@ func (*AskForWorkResponse) Descriptor() ([]byte, []int) {
 	return file_messages_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *AskForWorkResponse) GetWorkerId() int32 {
-	if x != nil {
-		return x.WorkerId
-	}
-	return 0
-}
-
-func (x *AskForWorkResponse) GetReducerNumber() int32 {
+func (x *AskForWorkResponse) GetTaskId() int32 {
 	if x != nil {
-		return x.ReducerNumber
+		return x.TaskId
 	}
 	return 0
 }
@@ -196,23 +229,188 @@ func (x *AskForWorkResponse) GetFilePath() string {
 	return ""
 }
 
-func (x *AskForWorkResponse) GetPlugin() string {
+func (x *AskForWorkResponse) GetResponse() string {
 	if x != nil {
-		return x.Plugin
+		return x.Response
 	}
 	return ""
 }
 
-func (x *AskForWorkResponse) GetResponse() string {
+func (x *AskForWorkResponse) GetJobId() string {
 	if x != nil {
-		return x.Response
+		return x.JobId
 	}
 	return ""
 }
 
-func (x *AskForWorkResponse) GetMapNumber() int32 {
+func (x *AskForWorkResponse) GetAttempt() int32 {
 	if x != nil {
-		return x.MapNumber
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *AskForWorkResponse) GetPartitionBounds() []string {
+	if x != nil {
+		return x.PartitionBounds
+	}
+	return nil
+}
+
+func (x *AskForWorkResponse) GetInputSplits() []*InputSplit {
+	if x != nil {
+		return x.InputSplits
+	}
+	return nil
+}
+
+func (x *AskForWorkResponse) GetMapOutputs() []*MapOutput {
+	if x != nil {
+		return x.MapOutputs
+	}
+	return nil
+}
+
+func (x *AskForWorkResponse) GetSkippedMapTaskIds() []int32 {
+	if x != nil {
+		return x.SkippedMapTaskIds
+	}
+	return nil
+}
+
+func (x *AskForWorkResponse) GetJobGeneration() int32 {
+	if x != nil {
+		return x.JobGeneration
+	}
+	return 0
+}
+
+func (x *AskForWorkResponse) GetMapOutputLocations() []string {
+	if x != nil {
+		return x.MapOutputLocations
+	}
+	return nil
+}
+
+type InputSplit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length        int64                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InputSplit) Reset() {
+	*x = InputSplit{}
+	mi := &file_messages_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InputSplit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InputSplit) ProtoMessage() {}
+
+func (x *InputSplit) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InputSplit.ProtoReflect.Descriptor instead.
+func (*InputSplit) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *InputSplit) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *InputSplit) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *InputSplit) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+// MapOutput es un archivo intermedio ya confirmado que un reduce tiene que leer. La ruta no viaja: el worker la arma
+// con el job, el map, el intento y la partición, así una asignación con muchos maps entra en un mensaje.
+type MapOutput struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MapTaskId int32                  `protobuf:"varint,1,opt,name=mapTaskId,proto3" json:"mapTaskId,omitempty"`
+	Attempt   int32                  `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// Posición + 1 en mapOutputLocations del worker que sirve la salida; 0 si no registró ninguno
+	LocationIndex int32 `protobuf:"varint,5,opt,name=locationIndex,proto3" json:"locationIndex,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MapOutput) Reset() {
+	*x = MapOutput{}
+	mi := &file_messages_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapOutput) ProtoMessage() {}
+
+func (x *MapOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapOutput.ProtoReflect.Descriptor instead.
+func (*MapOutput) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MapOutput) GetMapTaskId() int32 {
+	if x != nil {
+		return x.MapTaskId
+	}
+	return 0
+}
+
+func (x *MapOutput) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *MapOutput) GetLocationIndex() int32 {
+	if x != nil {
+		return x.LocationIndex
 	}
 	return 0
 }
@@ -220,13 +418,14 @@ func (x *AskForWorkResponse) GetMapNumber() int32 {
 type IFinishedResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Response      string                 `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	Accepted      bool                   `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *IFinishedResponse) Reset() {
 	*x = IFinishedResponse{}
-	mi := &file_messages_proto_msgTypes[3]
+	mi := &file_messages_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -238,7 +437,7 @@ func (x *IFinishedResponse) String() string {
 func (*IFinishedResponse) ProtoMessage() {}
 
 func (x *IFinishedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_messages_proto_msgTypes[3]
+	mi := &file_messages_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -251,7 +450,7 @@ func (x *IFinishedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IFinishedResponse.ProtoReflect.Descriptor instead.
 func (*IFinishedResponse) Descriptor() ([]byte, []int) {
-	return file_messages_proto_rawDescGZIP(), []int{3}
+	return file_messages_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *IFinishedResponse) GetResponse() string {
@@ -261,35 +460,984 @@ func (x *IFinishedResponse) GetResponse() string {
 	return ""
 }
 
-var File_messages_proto protoreflect.FileDescriptor
+func (x *IFinishedResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
 
-const file_messages_proto_rawDesc = "" +
-	"\n" +
-	"\x0emessages.proto\x12\bmessages\"k\n" +
-	"\tIFinished\x12\x1e\n" +
-	"\n" +
-	"workerUuid\x18\x01 \x01(\tR\n" +
-	"workerUuid\x12\"\n" +
-	"\fworkFinished\x18\x02 \x01(\tR\fworkFinished\x12\x1a\n" +
-	"\bworkType\x18\x03 \x01(\tR\bworkType\"(\n" +
-	"\x06ImFree\x12\x1e\n" +
-	"\n" +
-	"workerUuid\x18\x01 \x01(\tR\n" +
-	"workerUuid\"\xe0\x01\n" +
-	"\x12AskForWorkResponse\x12\x1a\n" +
-	"\bworkerId\x18\x01 \x01(\x05R\bworkerId\x12$\n" +
-	"\rreducerNumber\x18\x02 \x01(\x05R\rreducerNumber\x12\x1a\n" +
-	"\bworkType\x18\x03 \x01(\tR\bworkType\x12\x1a\n" +
-	"\bfilePath\x18\x04 \x01(\tR\bfilePath\x12\x16\n" +
-	"\x06plugin\x18\x05 \x01(\tR\x06plugin\x12\x1a\n" +
-	"\bresponse\x18\x06 \x01(\tR\bresponse\x12\x1c\n" +
-	"\tmapNumber\x18\a \x01(\x05R\tmapNumber\"/\n" +
-	"\x11IFinishedResponse\x12\x1a\n" +
-	"\bresponse\x18\x01 \x01(\tR\bresponse2\x8e\x01\n" +
+type ImAlive struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	WorkerUuid     string                 `protobuf:"bytes,1,opt,name=workerUuid,proto3" json:"workerUuid,omitempty"`
+	WorkInProgress string                 `protobuf:"bytes,2,opt,name=workInProgress,proto3" json:"workInProgress,omitempty"`
+	WorkType       string                 `protobuf:"bytes,3,opt,name=workType,proto3" json:"workType,omitempty"`
+	JobId          string                 `protobuf:"bytes,4,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	Attempt        int32                  `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Committing     bool                   `protobuf:"varint,6,opt,name=committing,proto3" json:"committing,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImAlive) Reset() {
+	*x = ImAlive{}
+	mi := &file_messages_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImAlive) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImAlive) ProtoMessage() {}
+
+func (x *ImAlive) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImAlive.ProtoReflect.Descriptor instead.
+func (*ImAlive) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ImAlive) GetWorkerUuid() string {
+	if x != nil {
+		return x.WorkerUuid
+	}
+	return ""
+}
+
+func (x *ImAlive) GetWorkInProgress() string {
+	if x != nil {
+		return x.WorkInProgress
+	}
+	return ""
+}
+
+func (x *ImAlive) GetWorkType() string {
+	if x != nil {
+		return x.WorkType
+	}
+	return ""
+}
+
+func (x *ImAlive) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ImAlive) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *ImAlive) GetCommitting() bool {
+	if x != nil {
+		return x.Committing
+	}
+	return false
+}
+
+type ImAliveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Response      string                 `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImAliveResponse) Reset() {
+	*x = ImAliveResponse{}
+	mi := &file_messages_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImAliveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImAliveResponse) ProtoMessage() {}
+
+func (x *ImAliveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImAliveResponse.ProtoReflect.Descriptor instead.
+func (*ImAliveResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ImAliveResponse) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+type JobSubmission struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	InputFiles         []string               `protobuf:"bytes,1,rep,name=inputFiles,proto3" json:"inputFiles,omitempty"`
+	ReducerNumber      int32                  `protobuf:"varint,2,opt,name=reducerNumber,proto3" json:"reducerNumber,omitempty"`
+	Plugin             string                 `protobuf:"bytes,3,opt,name=plugin,proto3" json:"plugin,omitempty"`
+	OutputPrefix       string                 `protobuf:"bytes,4,opt,name=outputPrefix,proto3" json:"outputPrefix,omitempty"`
+	TotalOrder         bool                   `protobuf:"varint,5,opt,name=totalOrder,proto3" json:"totalOrder,omitempty"`
+	IntermediateFormat string                 `protobuf:"bytes,6,opt,name=intermediateFormat,proto3" json:"intermediateFormat,omitempty"`
+	IntermediateCodec  string                 `protobuf:"bytes,7,opt,name=intermediateCodec,proto3" json:"intermediateCodec,omitempty"`
+	Include            []string               `protobuf:"bytes,8,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude            []string               `protobuf:"bytes,9,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *JobSubmission) Reset() {
+	*x = JobSubmission{}
+	mi := &file_messages_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobSubmission) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobSubmission) ProtoMessage() {}
+
+func (x *JobSubmission) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobSubmission.ProtoReflect.Descriptor instead.
+func (*JobSubmission) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *JobSubmission) GetInputFiles() []string {
+	if x != nil {
+		return x.InputFiles
+	}
+	return nil
+}
+
+func (x *JobSubmission) GetReducerNumber() int32 {
+	if x != nil {
+		return x.ReducerNumber
+	}
+	return 0
+}
+
+func (x *JobSubmission) GetPlugin() string {
+	if x != nil {
+		return x.Plugin
+	}
+	return ""
+}
+
+func (x *JobSubmission) GetOutputPrefix() string {
+	if x != nil {
+		return x.OutputPrefix
+	}
+	return ""
+}
+
+func (x *JobSubmission) GetTotalOrder() bool {
+	if x != nil {
+		return x.TotalOrder
+	}
+	return false
+}
+
+func (x *JobSubmission) GetIntermediateFormat() string {
+	if x != nil {
+		return x.IntermediateFormat
+	}
+	return ""
+}
+
+func (x *JobSubmission) GetIntermediateCodec() string {
+	if x != nil {
+		return x.IntermediateCodec
+	}
+	return ""
+}
+
+func (x *JobSubmission) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *JobSubmission) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+type JobSubmissionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	Response      string                 `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobSubmissionResponse) Reset() {
+	*x = JobSubmissionResponse{}
+	mi := &file_messages_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobSubmissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobSubmissionResponse) ProtoMessage() {}
+
+func (x *JobSubmissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobSubmissionResponse.ProtoReflect.Descriptor instead.
+func (*JobSubmissionResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *JobSubmissionResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobSubmissionResponse) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+type JobStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatusRequest) Reset() {
+	*x = JobStatusRequest{}
+	mi := &file_messages_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusRequest) ProtoMessage() {}
+
+func (x *JobStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusRequest.ProtoReflect.Descriptor instead.
+func (*JobStatusRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *JobStatusRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type JobStatusResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	JobId           string                 `protobuf:"bytes,1,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	JobStatus       string                 `protobuf:"bytes,2,opt,name=jobStatus,proto3" json:"jobStatus,omitempty"`
+	MapsToDo        int32                  `protobuf:"varint,3,opt,name=mapsToDo,proto3" json:"mapsToDo,omitempty"`
+	ReducesToDo     int32                  `protobuf:"varint,4,opt,name=reducesToDo,proto3" json:"reducesToDo,omitempty"`
+	TasksInProgress int32                  `protobuf:"varint,5,opt,name=tasksInProgress,proto3" json:"tasksInProgress,omitempty"`
+	ReclaimedTasks  int32                  `protobuf:"varint,6,opt,name=reclaimedTasks,proto3" json:"reclaimedTasks,omitempty"`
+	FailureReason   string                 `protobuf:"bytes,7,opt,name=failureReason,proto3" json:"failureReason,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *JobStatusResponse) Reset() {
+	*x = JobStatusResponse{}
+	mi := &file_messages_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusResponse) ProtoMessage() {}
+
+func (x *JobStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusResponse.ProtoReflect.Descriptor instead.
+func (*JobStatusResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *JobStatusResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetJobStatus() string {
+	if x != nil {
+		return x.JobStatus
+	}
+	return ""
+}
+
+func (x *JobStatusResponse) GetMapsToDo() int32 {
+	if x != nil {
+		return x.MapsToDo
+	}
+	return 0
+}
+
+func (x *JobStatusResponse) GetReducesToDo() int32 {
+	if x != nil {
+		return x.ReducesToDo
+	}
+	return 0
+}
+
+func (x *JobStatusResponse) GetTasksInProgress() int32 {
+	if x != nil {
+		return x.TasksInProgress
+	}
+	return 0
+}
+
+func (x *JobStatusResponse) GetReclaimedTasks() int32 {
+	if x != nil {
+		return x.ReclaimedTasks
+	}
+	return 0
+}
+
+func (x *JobStatusResponse) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+type IFailed struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	WorkerUuid        string                 `protobuf:"bytes,1,opt,name=workerUuid,proto3" json:"workerUuid,omitempty"`
+	WorkFailed        string                 `protobuf:"bytes,2,opt,name=workFailed,proto3" json:"workFailed,omitempty"`
+	WorkType          string                 `protobuf:"bytes,3,opt,name=workType,proto3" json:"workType,omitempty"`
+	JobId             string                 `protobuf:"bytes,4,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	Attempt           int32                  `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	ErrorMessage      string                 `protobuf:"bytes,6,opt,name=errorMessage,proto3" json:"errorMessage,omitempty"`
+	MissingMapOutputs []*MapOutput           `protobuf:"bytes,7,rep,name=missingMapOutputs,proto3" json:"missingMapOutputs,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *IFailed) Reset() {
+	*x = IFailed{}
+	mi := &file_messages_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IFailed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IFailed) ProtoMessage() {}
+
+func (x *IFailed) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IFailed.ProtoReflect.Descriptor instead.
+func (*IFailed) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *IFailed) GetWorkerUuid() string {
+	if x != nil {
+		return x.WorkerUuid
+	}
+	return ""
+}
+
+func (x *IFailed) GetWorkFailed() string {
+	if x != nil {
+		return x.WorkFailed
+	}
+	return ""
+}
+
+func (x *IFailed) GetWorkType() string {
+	if x != nil {
+		return x.WorkType
+	}
+	return ""
+}
+
+func (x *IFailed) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *IFailed) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *IFailed) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *IFailed) GetMissingMapOutputs() []*MapOutput {
+	if x != nil {
+		return x.MissingMapOutputs
+	}
+	return nil
+}
+
+type IFailedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Response      string                 `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	Accepted      bool                   `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IFailedResponse) Reset() {
+	*x = IFailedResponse{}
+	mi := &file_messages_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IFailedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IFailedResponse) ProtoMessage() {}
+
+func (x *IFailedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IFailedResponse.ProtoReflect.Descriptor instead.
+func (*IFailedResponse) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IFailedResponse) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *IFailedResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+type JobConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobConfigRequest) Reset() {
+	*x = JobConfigRequest{}
+	mi := &file_messages_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobConfigRequest) ProtoMessage() {}
+
+func (x *JobConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobConfigRequest.ProtoReflect.Descriptor instead.
+func (*JobConfigRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *JobConfigRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type JobConfig struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	JobId              string                 `protobuf:"bytes,1,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	ReducerNumber      int32                  `protobuf:"varint,2,opt,name=reducerNumber,proto3" json:"reducerNumber,omitempty"`
+	MapNumber          int32                  `protobuf:"varint,3,opt,name=mapNumber,proto3" json:"mapNumber,omitempty"`
+	Plugin             string                 `protobuf:"bytes,4,opt,name=plugin,proto3" json:"plugin,omitempty"`
+	OutputPrefix       string                 `protobuf:"bytes,5,opt,name=outputPrefix,proto3" json:"outputPrefix,omitempty"`
+	TotalOrder         bool                   `protobuf:"varint,6,opt,name=totalOrder,proto3" json:"totalOrder,omitempty"`
+	IntermediateFormat string                 `protobuf:"bytes,7,opt,name=intermediateFormat,proto3" json:"intermediateFormat,omitempty"`
+	IntermediateCodec  string                 `protobuf:"bytes,8,opt,name=intermediateCodec,proto3" json:"intermediateCodec,omitempty"`
+	Generation         int32                  `protobuf:"varint,9,opt,name=generation,proto3" json:"generation,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *JobConfig) Reset() {
+	*x = JobConfig{}
+	mi := &file_messages_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobConfig) ProtoMessage() {}
+
+func (x *JobConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobConfig.ProtoReflect.Descriptor instead.
+func (*JobConfig) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *JobConfig) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobConfig) GetReducerNumber() int32 {
+	if x != nil {
+		return x.ReducerNumber
+	}
+	return 0
+}
+
+func (x *JobConfig) GetMapNumber() int32 {
+	if x != nil {
+		return x.MapNumber
+	}
+	return 0
+}
+
+func (x *JobConfig) GetPlugin() string {
+	if x != nil {
+		return x.Plugin
+	}
+	return ""
+}
+
+func (x *JobConfig) GetOutputPrefix() string {
+	if x != nil {
+		return x.OutputPrefix
+	}
+	return ""
+}
+
+func (x *JobConfig) GetTotalOrder() bool {
+	if x != nil {
+		return x.TotalOrder
+	}
+	return false
+}
+
+func (x *JobConfig) GetIntermediateFormat() string {
+	if x != nil {
+		return x.IntermediateFormat
+	}
+	return ""
+}
+
+func (x *JobConfig) GetIntermediateCodec() string {
+	if x != nil {
+		return x.IntermediateCodec
+	}
+	return ""
+}
+
+func (x *JobConfig) GetGeneration() int32 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+type PartitionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=jobId,proto3" json:"jobId,omitempty"`
+	MapTaskId     int32                  `protobuf:"varint,2,opt,name=mapTaskId,proto3" json:"mapTaskId,omitempty"`
+	Attempt       int32                  `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Partition     int32                  `protobuf:"varint,4,opt,name=partition,proto3" json:"partition,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartitionRequest) Reset() {
+	*x = PartitionRequest{}
+	mi := &file_messages_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartitionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartitionRequest) ProtoMessage() {}
+
+func (x *PartitionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartitionRequest.ProtoReflect.Descriptor instead.
+func (*PartitionRequest) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PartitionRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *PartitionRequest) GetMapTaskId() int32 {
+	if x != nil {
+		return x.MapTaskId
+	}
+	return 0
+}
+
+func (x *PartitionRequest) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *PartitionRequest) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+type PartitionChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartitionChunk) Reset() {
+	*x = PartitionChunk{}
+	mi := &file_messages_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartitionChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartitionChunk) ProtoMessage() {}
+
+func (x *PartitionChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_messages_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartitionChunk.ProtoReflect.Descriptor instead.
+func (*PartitionChunk) Descriptor() ([]byte, []int) {
+	return file_messages_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PartitionChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_messages_proto protoreflect.FileDescriptor
+
+const file_messages_proto_rawDesc = "" +
+	"\n" +
+	"\x0emessages.proto\x12\bmessages\"\xe3\x01\n" +
+	"\tIFinished\x12\x1e\n" +
+	"\n" +
+	"workerUuid\x18\x01 \x01(\tR\n" +
+	"workerUuid\x12\"\n" +
+	"\fworkFinished\x18\x02 \x01(\tR\fworkFinished\x12\x1a\n" +
+	"\bworkType\x18\x03 \x01(\tR\bworkType\x12\x14\n" +
+	"\x05jobId\x18\x04 \x01(\tR\x05jobId\x12\x18\n" +
+	"\aattempt\x18\x05 \x01(\x05R\aattempt\x12\x1e\n" +
+	"\n" +
+	"sampleKeys\x18\x06 \x03(\tR\n" +
+	"sampleKeys\x12&\n" +
+	"\x0eshuffleAddress\x18\a \x01(\tR\x0eshuffleAddress\"(\n" +
+	"\x06ImFree\x12\x1e\n" +
+	"\n" +
+	"workerUuid\x18\x01 \x01(\tR\n" +
+	"workerUuid\"\xfb\x03\n" +
+	"\x12AskForWorkResponse\x12\x16\n" +
+	"\x06taskId\x18\x01 \x01(\x05R\x06taskId\x12\x1a\n" +
+	"\bworkType\x18\x03 \x01(\tR\bworkType\x12\x1a\n" +
+	"\bfilePath\x18\x04 \x01(\tR\bfilePath\x12\x1a\n" +
+	"\bresponse\x18\x06 \x01(\tR\bresponse\x12\x14\n" +
+	"\x05jobId\x18\b \x01(\tR\x05jobId\x12\x18\n" +
+	"\aattempt\x18\n" +
+	" \x01(\x05R\aattempt\x12(\n" +
+	"\x0fpartitionBounds\x18\f \x03(\tR\x0fpartitionBounds\x126\n" +
+	"\vinputSplits\x18\x10 \x03(\v2\x14.messages.InputSplitR\vinputSplits\x123\n" +
+	"\n" +
+	"mapOutputs\x18\x11 \x03(\v2\x13.messages.MapOutputR\n" +
+	"mapOutputs\x12,\n" +
+	"\x11skippedMapTaskIds\x18\x12 \x03(\x05R\x11skippedMapTaskIds\x12$\n" +
+	"\rjobGeneration\x18\x13 \x01(\x05R\rjobGeneration\x12.\n" +
+	"\x12mapOutputLocations\x18\x14 \x03(\tR\x12mapOutputLocationsJ\x04\b\x02\x10\x03J\x04\b\x05\x10\x06J\x04\b\a\x10\bJ\x04\b\t\x10\n" +
+	"J\x04\b\v\x10\fJ\x04\b\r\x10\x0eJ\x04\b\x0e\x10\x0fJ\x04\b\x0f\x10\x10\"P\n" +
+	"\n" +
+	"InputSplit\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x03R\x06length\"u\n" +
+	"\tMapOutput\x12\x1c\n" +
+	"\tmapTaskId\x18\x01 \x01(\x05R\tmapTaskId\x12\x18\n" +
+	"\aattempt\x18\x03 \x01(\x05R\aattempt\x12$\n" +
+	"\rlocationIndex\x18\x05 \x01(\x05R\rlocationIndexJ\x04\b\x02\x10\x03J\x04\b\x04\x10\x05\"K\n" +
+	"\x11IFinishedResponse\x12\x1a\n" +
+	"\bresponse\x18\x01 \x01(\tR\bresponse\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\bR\baccepted\"\xbd\x01\n" +
+	"\aImAlive\x12\x1e\n" +
+	"\n" +
+	"workerUuid\x18\x01 \x01(\tR\n" +
+	"workerUuid\x12&\n" +
+	"\x0eworkInProgress\x18\x02 \x01(\tR\x0eworkInProgress\x12\x1a\n" +
+	"\bworkType\x18\x03 \x01(\tR\bworkType\x12\x14\n" +
+	"\x05jobId\x18\x04 \x01(\tR\x05jobId\x12\x18\n" +
+	"\aattempt\x18\x05 \x01(\x05R\aattempt\x12\x1e\n" +
+	"\n" +
+	"committing\x18\x06 \x01(\bR\n" +
+	"committing\"-\n" +
+	"\x0fImAliveResponse\x12\x1a\n" +
+	"\bresponse\x18\x01 \x01(\tR\bresponse\"\xc3\x02\n" +
+	"\rJobSubmission\x12\x1e\n" +
+	"\n" +
+	"inputFiles\x18\x01 \x03(\tR\n" +
+	"inputFiles\x12$\n" +
+	"\rreducerNumber\x18\x02 \x01(\x05R\rreducerNumber\x12\x16\n" +
+	"\x06plugin\x18\x03 \x01(\tR\x06plugin\x12\"\n" +
+	"\foutputPrefix\x18\x04 \x01(\tR\foutputPrefix\x12\x1e\n" +
+	"\n" +
+	"totalOrder\x18\x05 \x01(\bR\n" +
+	"totalOrder\x12.\n" +
+	"\x12intermediateFormat\x18\x06 \x01(\tR\x12intermediateFormat\x12,\n" +
+	"\x11intermediateCodec\x18\a \x01(\tR\x11intermediateCodec\x12\x18\n" +
+	"\ainclude\x18\b \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\t \x03(\tR\aexclude\"I\n" +
+	"\x15JobSubmissionResponse\x12\x14\n" +
+	"\x05jobId\x18\x01 \x01(\tR\x05jobId\x12\x1a\n" +
+	"\bresponse\x18\x02 \x01(\tR\bresponse\"(\n" +
+	"\x10JobStatusRequest\x12\x14\n" +
+	"\x05jobId\x18\x01 \x01(\tR\x05jobId\"\xfd\x01\n" +
+	"\x11JobStatusResponse\x12\x14\n" +
+	"\x05jobId\x18\x01 \x01(\tR\x05jobId\x12\x1c\n" +
+	"\tjobStatus\x18\x02 \x01(\tR\tjobStatus\x12\x1a\n" +
+	"\bmapsToDo\x18\x03 \x01(\x05R\bmapsToDo\x12 \n" +
+	"\vreducesToDo\x18\x04 \x01(\x05R\vreducesToDo\x12(\n" +
+	"\x0ftasksInProgress\x18\x05 \x01(\x05R\x0ftasksInProgress\x12&\n" +
+	"\x0ereclaimedTasks\x18\x06 \x01(\x05R\x0ereclaimedTasks\x12$\n" +
+	"\rfailureReason\x18\a \x01(\tR\rfailureReason\"\xfc\x01\n" +
+	"\aIFailed\x12\x1e\n" +
+	"\n" +
+	"workerUuid\x18\x01 \x01(\tR\n" +
+	"workerUuid\x12\x1e\n" +
+	"\n" +
+	"workFailed\x18\x02 \x01(\tR\n" +
+	"workFailed\x12\x1a\n" +
+	"\bworkType\x18\x03 \x01(\tR\bworkType\x12\x14\n" +
+	"\x05jobId\x18\x04 \x01(\tR\x05jobId\x12\x18\n" +
+	"\aattempt\x18\x05 \x01(\x05R\aattempt\x12\"\n" +
+	"\ferrorMessage\x18\x06 \x01(\tR\ferrorMessage\x12A\n" +
+	"\x11missingMapOutputs\x18\a \x03(\v2\x13.messages.MapOutputR\x11missingMapOutputs\"I\n" +
+	"\x0fIFailedResponse\x12\x1a\n" +
+	"\bresponse\x18\x01 \x01(\tR\bresponse\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\bR\baccepted\"(\n" +
+	"\x10JobConfigRequest\x12\x14\n" +
+	"\x05jobId\x18\x01 \x01(\tR\x05jobId\"\xbf\x02\n" +
+	"\tJobConfig\x12\x14\n" +
+	"\x05jobId\x18\x01 \x01(\tR\x05jobId\x12$\n" +
+	"\rreducerNumber\x18\x02 \x01(\x05R\rreducerNumber\x12\x1c\n" +
+	"\tmapNumber\x18\x03 \x01(\x05R\tmapNumber\x12\x16\n" +
+	"\x06plugin\x18\x04 \x01(\tR\x06plugin\x12\"\n" +
+	"\foutputPrefix\x18\x05 \x01(\tR\foutputPrefix\x12\x1e\n" +
+	"\n" +
+	"totalOrder\x18\x06 \x01(\bR\n" +
+	"totalOrder\x12.\n" +
+	"\x12intermediateFormat\x18\a \x01(\tR\x12intermediateFormat\x12,\n" +
+	"\x11intermediateCodec\x18\b \x01(\tR\x11intermediateCodec\x12\x1e\n" +
+	"\n" +
+	"generation\x18\t \x01(\x05R\n" +
+	"generation\"~\n" +
+	"\x10PartitionRequest\x12\x14\n" +
+	"\x05jobId\x18\x01 \x01(\tR\x05jobId\x12\x1c\n" +
+	"\tmapTaskId\x18\x02 \x01(\x05R\tmapTaskId\x12\x18\n" +
+	"\aattempt\x18\x03 \x01(\x05R\aattempt\x12\x1c\n" +
+	"\tpartition\x18\x04 \x01(\x05R\tpartition\"$\n" +
+	"\x0ePartitionChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data2\xd9\x03\n" +
 	"\x06Server\x12<\n" +
 	"\n" +
 	"AskForWork\x12\x10.messages.ImFree\x1a\x1c.messages.AskForWorkResponse\x12F\n" +
-	"\x12MarkWorkAsFinished\x12\x13.messages.IFinished\x1a\x1b.messages.IFinishedResponseB\fZ\n" +
+	"\x12MarkWorkAsFinished\x12\x13.messages.IFinished\x1a\x1b.messages.IFinishedResponse\x129\n" +
+	"\tHeartbeat\x12\x11.messages.ImAlive\x1a\x19.messages.ImAliveResponse\x12E\n" +
+	"\tSubmitJob\x12\x17.messages.JobSubmission\x1a\x1f.messages.JobSubmissionResponse\x12G\n" +
+	"\fGetJobStatus\x12\x1a.messages.JobStatusRequest\x1a\x1b.messages.JobStatusResponse\x12=\n" +
+	"\rReportFailure\x12\x11.messages.IFailed\x1a\x19.messages.IFailedResponse\x12?\n" +
+	"\fGetJobConfig\x12\x1a.messages.JobConfigRequest\x1a\x13.messages.JobConfig2S\n" +
+	"\aShuffle\x12H\n" +
+	"\x0eFetchPartition\x12\x1a.messages.PartitionRequest\x1a\x18.messages.PartitionChunk0\x01B\fZ\n" +
 	"./messagesb\x06proto3"
 
 var (
@@ -304,23 +1452,52 @@ func file_messages_proto_rawDescGZIP() []byte {
 	return file_messages_proto_rawDescData
 }
 
-var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_messages_proto_goTypes = []any{
-	(*IFinished)(nil),          // 0: messages.IFinished
-	(*ImFree)(nil),             // 1: messages.ImFree
-	(*AskForWorkResponse)(nil), // 2: messages.AskForWorkResponse
-	(*IFinishedResponse)(nil),  // 3: messages.IFinishedResponse
+	(*IFinished)(nil),             // 0: messages.IFinished
+	(*ImFree)(nil),                // 1: messages.ImFree
+	(*AskForWorkResponse)(nil),    // 2: messages.AskForWorkResponse
+	(*InputSplit)(nil),            // 3: messages.InputSplit
+	(*MapOutput)(nil),             // 4: messages.MapOutput
+	(*IFinishedResponse)(nil),     // 5: messages.IFinishedResponse
+	(*ImAlive)(nil),               // 6: messages.ImAlive
+	(*ImAliveResponse)(nil),       // 7: messages.ImAliveResponse
+	(*JobSubmission)(nil),         // 8: messages.JobSubmission
+	(*JobSubmissionResponse)(nil), // 9: messages.JobSubmissionResponse
+	(*JobStatusRequest)(nil),      // 10: messages.JobStatusRequest
+	(*JobStatusResponse)(nil),     // 11: messages.JobStatusResponse
+	(*IFailed)(nil),               // 12: messages.IFailed
+	(*IFailedResponse)(nil),       // 13: messages.IFailedResponse
+	(*JobConfigRequest)(nil),      // 14: messages.JobConfigRequest
+	(*JobConfig)(nil),             // 15: messages.JobConfig
+	(*PartitionRequest)(nil),      // 16: messages.PartitionRequest
+	(*PartitionChunk)(nil),        // 17: messages.PartitionChunk
 }
 var file_messages_proto_depIdxs = []int32{
-	1, // 0: messages.Server.AskForWork:input_type -> messages.ImFree
-	0, // 1: messages.Server.MarkWorkAsFinished:input_type -> messages.IFinished
-	2, // 2: messages.Server.AskForWork:output_type -> messages.AskForWorkResponse
-	3, // 3: messages.Server.MarkWorkAsFinished:output_type -> messages.IFinishedResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	3,  // 0: messages.AskForWorkResponse.inputSplits:type_name -> messages.InputSplit
+	4,  // 1: messages.AskForWorkResponse.mapOutputs:type_name -> messages.MapOutput
+	4,  // 2: messages.IFailed.missingMapOutputs:type_name -> messages.MapOutput
+	1,  // 3: messages.Server.AskForWork:input_type -> messages.ImFree
+	0,  // 4: messages.Server.MarkWorkAsFinished:input_type -> messages.IFinished
+	6,  // 5: messages.Server.Heartbeat:input_type -> messages.ImAlive
+	8,  // 6: messages.Server.SubmitJob:input_type -> messages.JobSubmission
+	10, // 7: messages.Server.GetJobStatus:input_type -> messages.JobStatusRequest
+	12, // 8: messages.Server.ReportFailure:input_type -> messages.IFailed
+	14, // 9: messages.Server.GetJobConfig:input_type -> messages.JobConfigRequest
+	16, // 10: messages.Shuffle.FetchPartition:input_type -> messages.PartitionRequest
+	2,  // 11: messages.Server.AskForWork:output_type -> messages.AskForWorkResponse
+	5,  // 12: messages.Server.MarkWorkAsFinished:output_type -> messages.IFinishedResponse
+	7,  // 13: messages.Server.Heartbeat:output_type -> messages.ImAliveResponse
+	9,  // 14: messages.Server.SubmitJob:output_type -> messages.JobSubmissionResponse
+	11, // 15: messages.Server.GetJobStatus:output_type -> messages.JobStatusResponse
+	13, // 16: messages.Server.ReportFailure:output_type -> messages.IFailedResponse
+	15, // 17: messages.Server.GetJobConfig:output_type -> messages.JobConfig
+	17, // 18: messages.Shuffle.FetchPartition:output_type -> messages.PartitionChunk
+	11, // [11:19] is the sub-list for method output_type
+	3,  // [3:11] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -334,9 +1511,9 @@ func file_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messages_proto_rawDesc), len(file_messages_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   18,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_messages_proto_goTypes,
 		DependencyIndexes: file_messages_proto_depIdxs,
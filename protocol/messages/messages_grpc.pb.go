@@ -21,6 +21,11 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	Server_AskForWork_FullMethodName         = "/messages.Server/AskForWork"
 	Server_MarkWorkAsFinished_FullMethodName = "/messages.Server/MarkWorkAsFinished"
+	Server_Heartbeat_FullMethodName          = "/messages.Server/Heartbeat"
+	Server_SubmitJob_FullMethodName          = "/messages.Server/SubmitJob"
+	Server_GetJobStatus_FullMethodName       = "/messages.Server/GetJobStatus"
+	Server_ReportFailure_FullMethodName      = "/messages.Server/ReportFailure"
+	Server_GetJobConfig_FullMethodName       = "/messages.Server/GetJobConfig"
 )
 
 // ServerClient is the client API for Server service.
@@ -29,6 +34,11 @@ const (
 type ServerClient interface {
 	AskForWork(ctx context.Context, in *ImFree, opts ...grpc.CallOption) (*AskForWorkResponse, error)
 	MarkWorkAsFinished(ctx context.Context, in *IFinished, opts ...grpc.CallOption) (*IFinishedResponse, error)
+	Heartbeat(ctx context.Context, in *ImAlive, opts ...grpc.CallOption) (*ImAliveResponse, error)
+	SubmitJob(ctx context.Context, in *JobSubmission, opts ...grpc.CallOption) (*JobSubmissionResponse, error)
+	GetJobStatus(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (*JobStatusResponse, error)
+	ReportFailure(ctx context.Context, in *IFailed, opts ...grpc.CallOption) (*IFailedResponse, error)
+	GetJobConfig(ctx context.Context, in *JobConfigRequest, opts ...grpc.CallOption) (*JobConfig, error)
 }
 
 type serverClient struct {
@@ -59,12 +69,67 @@ func (c *serverClient) MarkWorkAsFinished(ctx context.Context, in *IFinished, op
 	return out, nil
 }
 
+func (c *serverClient) Heartbeat(ctx context.Context, in *ImAlive, opts ...grpc.CallOption) (*ImAliveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImAliveResponse)
+	err := c.cc.Invoke(ctx, Server_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverClient) SubmitJob(ctx context.Context, in *JobSubmission, opts ...grpc.CallOption) (*JobSubmissionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobSubmissionResponse)
+	err := c.cc.Invoke(ctx, Server_SubmitJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverClient) GetJobStatus(ctx context.Context, in *JobStatusRequest, opts ...grpc.CallOption) (*JobStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatusResponse)
+	err := c.cc.Invoke(ctx, Server_GetJobStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverClient) ReportFailure(ctx context.Context, in *IFailed, opts ...grpc.CallOption) (*IFailedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IFailedResponse)
+	err := c.cc.Invoke(ctx, Server_ReportFailure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverClient) GetJobConfig(ctx context.Context, in *JobConfigRequest, opts ...grpc.CallOption) (*JobConfig, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobConfig)
+	err := c.cc.Invoke(ctx, Server_GetJobConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ServerServer is the server API for Server service.
 // All implementations must embed UnimplementedServerServer
 // for forward compatibility.
 type ServerServer interface {
 	AskForWork(context.Context, *ImFree) (*AskForWorkResponse, error)
 	MarkWorkAsFinished(context.Context, *IFinished) (*IFinishedResponse, error)
+	Heartbeat(context.Context, *ImAlive) (*ImAliveResponse, error)
+	SubmitJob(context.Context, *JobSubmission) (*JobSubmissionResponse, error)
+	GetJobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error)
+	ReportFailure(context.Context, *IFailed) (*IFailedResponse, error)
+	GetJobConfig(context.Context, *JobConfigRequest) (*JobConfig, error)
 	mustEmbedUnimplementedServerServer()
 }
 
@@ -81,6 +146,21 @@ func (UnimplementedServerServer) AskForWork(context.Context, *ImFree) (*AskForWo
 func (UnimplementedServerServer) MarkWorkAsFinished(context.Context, *IFinished) (*IFinishedResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MarkWorkAsFinished not implemented")
 }
+func (UnimplementedServerServer) Heartbeat(context.Context, *ImAlive) (*ImAliveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedServerServer) SubmitJob(context.Context, *JobSubmission) (*JobSubmissionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedServerServer) GetJobStatus(context.Context, *JobStatusRequest) (*JobStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
+}
+func (UnimplementedServerServer) ReportFailure(context.Context, *IFailed) (*IFailedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportFailure not implemented")
+}
+func (UnimplementedServerServer) GetJobConfig(context.Context, *JobConfigRequest) (*JobConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobConfig not implemented")
+}
 func (UnimplementedServerServer) mustEmbedUnimplementedServerServer() {}
 func (UnimplementedServerServer) testEmbeddedByValue()                {}
 
@@ -138,6 +218,96 @@ func _Server_MarkWorkAsFinished_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Server_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImAlive)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Server_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerServer).Heartbeat(ctx, req.(*ImAlive))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Server_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobSubmission)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Server_SubmitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerServer).SubmitJob(ctx, req.(*JobSubmission))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Server_GetJobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerServer).GetJobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Server_GetJobStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerServer).GetJobStatus(ctx, req.(*JobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Server_ReportFailure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IFailed)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerServer).ReportFailure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Server_ReportFailure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerServer).ReportFailure(ctx, req.(*IFailed))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Server_GetJobConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerServer).GetJobConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Server_GetJobConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerServer).GetJobConfig(ctx, req.(*JobConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Server_ServiceDesc is the grpc.ServiceDesc for Server service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,7 +323,136 @@ var Server_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MarkWorkAsFinished",
 			Handler:    _Server_MarkWorkAsFinished_Handler,
 		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _Server_Heartbeat_Handler,
+		},
+		{
+			MethodName: "SubmitJob",
+			Handler:    _Server_SubmitJob_Handler,
+		},
+		{
+			MethodName: "GetJobStatus",
+			Handler:    _Server_GetJobStatus_Handler,
+		},
+		{
+			MethodName: "ReportFailure",
+			Handler:    _Server_ReportFailure_Handler,
+		},
+		{
+			MethodName: "GetJobConfig",
+			Handler:    _Server_GetJobConfig_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "messages.proto",
 }
+
+const (
+	Shuffle_FetchPartition_FullMethodName = "/messages.Shuffle/FetchPartition"
+)
+
+// ShuffleClient is the client API for Shuffle service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Shuffle lo sirve cada worker para que los reduces le pidan las particiones que escribieron sus maps
+type ShuffleClient interface {
+	FetchPartition(ctx context.Context, in *PartitionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartitionChunk], error)
+}
+
+type shuffleClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewShuffleClient(cc grpc.ClientConnInterface) ShuffleClient {
+	return &shuffleClient{cc}
+}
+
+func (c *shuffleClient) FetchPartition(ctx context.Context, in *PartitionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PartitionChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Shuffle_ServiceDesc.Streams[0], Shuffle_FetchPartition_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PartitionRequest, PartitionChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shuffle_FetchPartitionClient = grpc.ServerStreamingClient[PartitionChunk]
+
+// ShuffleServer is the server API for Shuffle service.
+// All implementations must embed UnimplementedShuffleServer
+// for forward compatibility.
+//
+// Shuffle lo sirve cada worker para que los reduces le pidan las particiones que escribieron sus maps
+type ShuffleServer interface {
+	FetchPartition(*PartitionRequest, grpc.ServerStreamingServer[PartitionChunk]) error
+	mustEmbedUnimplementedShuffleServer()
+}
+
+// UnimplementedShuffleServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedShuffleServer struct{}
+
+func (UnimplementedShuffleServer) FetchPartition(*PartitionRequest, grpc.ServerStreamingServer[PartitionChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method FetchPartition not implemented")
+}
+func (UnimplementedShuffleServer) mustEmbedUnimplementedShuffleServer() {}
+func (UnimplementedShuffleServer) testEmbeddedByValue()                 {}
+
+// UnsafeShuffleServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ShuffleServer will
+// result in compilation errors.
+type UnsafeShuffleServer interface {
+	mustEmbedUnimplementedShuffleServer()
+}
+
+func RegisterShuffleServer(s grpc.ServiceRegistrar, srv ShuffleServer) {
+	// If the following call pancis, it indicates UnimplementedShuffleServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Shuffle_ServiceDesc, srv)
+}
+
+func _Shuffle_FetchPartition_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PartitionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShuffleServer).FetchPartition(m, &grpc.GenericServerStream[PartitionRequest, PartitionChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shuffle_FetchPartitionServer = grpc.ServerStreamingServer[PartitionChunk]
+
+// Shuffle_ServiceDesc is the grpc.ServiceDesc for Shuffle service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Shuffle_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "messages.Shuffle",
+	HandlerType: (*ShuffleServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FetchPartition",
+			Handler:       _Shuffle_FetchPartition_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "messages.proto",
+}
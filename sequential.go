@@ -1,6 +1,7 @@
 package main
 
 import (
+    "flag"
     "fmt"
     "io/ioutil"
     "log"
@@ -8,16 +9,42 @@ import (
     "plugin"
     "sort"
     "tp1/mr"
+    "tp1/pkg/inputs"
 )
 
+func combine(combineF func(string, []string) []string, kva []mr.KeyValue) []mr.KeyValue {
+	groups := make(map[string][]string)
+	var keys []string
+	for _, kv := range kva {
+		if _, exists := groups[kv.Key]; !exists {
+			keys = append(keys, kv.Key)
+		}
+		groups[kv.Key] = append(groups[kv.Key], kv.Value)
+	}
+
+	var combined []mr.KeyValue
+	for _, key := range keys {
+		for _, value := range combineF(key, groups[key]) {
+			combined = append(combined, mr.KeyValue{Key: key, Value: value})
+		}
+	}
+	return combined
+}
+
 func main() {
-	if len(os.Args) < 3 {
-		fmt.Fprintf(os.Stderr, "Uso: go run sequential.go plugin.so inputfiles...\n")
+	inputFilters := inputs.RegisterFilterFlags(flag.CommandLine)
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		fmt.Fprintf(os.Stderr, "Uso: go run sequential.go [-include patron] [-exclude patron] plugin.so entradas...\n")
 		os.Exit(1)
 	}
 
-	pluginFile := os.Args[1]
-	inputFiles := os.Args[2:]
+	pluginFile := flag.Arg(0)
+	inputFiles, err := inputs.Resolve(flag.Args()[1:], *inputFilters)
+	if err != nil {
+		log.Fatalf("Error resolviendo las entradas: %v", err)
+	}
 
 	plug, err := plugin.Open(pluginFile)
 	if err != nil {
@@ -37,6 +64,12 @@ func main() {
 	mapF := mapFunc.(func(string, string) []mr.KeyValue)
 	reduceF := reduceFunc.(func(string, []string) string)
 
+	// Combine es opcional; si está, se aplica a la salida de cada archivo como lo hacen los maps distribuidos
+	var combineF func(string, []string) []string
+	if combineFunc, err := plug.Lookup("Combine"); err == nil {
+		combineF = combineFunc.(func(string, []string) []string)
+	}
+
 	fmt.Println("Ejecutando fase Map...")
 	var intermediate []mr.KeyValue
 	
@@ -49,6 +82,9 @@ func main() {
 		}
 
 		kva := mapF(filename, string(content))
+		if combineF != nil {
+			kva = combine(combineF, kva)
+		}
 		intermediate = append(intermediate, kva...)
 	}
 
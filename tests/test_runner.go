@@ -71,10 +71,11 @@ func (tr *TestRunner) runSequential(plugin string) (map[string]string, error) {
 	return tr.readResults("mr-out-*")
 }
 
-func (tr *TestRunner) runDistributed(plugin string) (map[string]string, error) {
+func (tr *TestRunner) runDistributed(plugin string, coordinatorFlags ...string) (map[string]string, error) {
 	tr.cleanup()
 
-	coordinatorArgs := []string{"run", "coordinator/coordinator.go", "3"}
+	coordinatorArgs := append([]string{"run", "coordinator/coordinator.go"}, coordinatorFlags...)
+	coordinatorArgs = append(coordinatorArgs, "3")
 	coordinatorArgs = append(coordinatorArgs, tr.inputFiles...)
 
 	coordinatorCmd := exec.Command("go", coordinatorArgs...)
@@ -198,6 +199,87 @@ func (tr *TestRunner) compareResults(sequential, distributed map[string]string)
 	return true, "Resultados idénticos (secuencial vs distribuido combinado)"
 }
 
+// checkTotalOrder verifica que mr-out-1, ..., mr-out-N concatenados en ese orden queden ordenados por clave.
+func (tr *TestRunner) checkTotalOrder(distributed map[string]string) (bool, string) {
+	var lastKey string
+	var lastFile string
+
+	for i := 1; i <= len(distributed); i++ {
+		filename := fmt.Sprintf("mr-out-%d", i)
+		content, exists := distributed[filename]
+		if !exists {
+			return false, fmt.Sprintf("Falta el archivo %s", filename)
+		}
+		if content == "" {
+			continue
+		}
+
+		for _, line := range strings.Split(content, "\n") {
+			key := strings.Fields(line)[0]
+			if key < lastKey {
+				return false, fmt.Sprintf("La clave %q de %s aparece después de %q de %s", key, filename, lastKey, lastFile)
+			}
+			lastKey, lastFile = key, filename
+		}
+	}
+
+	return true, "Salida ordenada globalmente"
+}
+
+// configTest corre un plugin con flags del coordinator que cambian cómo se reparte el trabajo pero no el resultado.
+type configTest struct {
+	name             string
+	plugin           string
+	coordinatorFlags []string
+	totalOrder       bool
+}
+
+func (tr *TestRunner) runConfigTest(test configTest) TestResult {
+	fmt.Printf("Ejecutando %s (%s)...\n", test.name, strings.Join(test.coordinatorFlags, " "))
+
+	fmt.Printf("  - Ejecutando versión secuencial...")
+	sequential, err := tr.runSequential(test.plugin)
+	if err != nil {
+		return TestResult{
+			TestName: test.name,
+			Passed:   false,
+			Error:    fmt.Sprintf("Error en versión secuencial: %v", err),
+		}
+	}
+	fmt.Printf(" ✓\n")
+
+	fmt.Printf("  - Ejecutando versión distribuida...")
+	distributed, err := tr.runDistributed(test.plugin, test.coordinatorFlags...)
+	if err != nil {
+		return TestResult{
+			TestName: test.name,
+			Passed:   false,
+			Error:    fmt.Sprintf("Error en versión distribuida: %v", err),
+		}
+	}
+	fmt.Printf(" ✓\n")
+
+	fmt.Printf("  - Comparando resultados...")
+	passed, message := tr.compareResults(sequential, distributed)
+	if passed && test.totalOrder {
+		passed, message = tr.checkTotalOrder(distributed)
+	}
+	fmt.Printf(" %s\n", func() string {
+		if passed {
+			return "✓"
+		}
+		return "✗"
+	}())
+
+	return TestResult{
+		TestName:    test.name,
+		Sequential:  sequential,
+		Distributed: distributed,
+		Passed:      passed,
+		Error:       message,
+	}
+}
+
 func (tr *TestRunner) runTest(plugin string) TestResult {
 	testName := fmt.Sprintf("Test_%s", strings.TrimSuffix(plugin, ".so"))
 
@@ -469,6 +551,16 @@ func (tr *TestRunner) runAllTests() []TestResult {
 		fmt.Println()
 	}
 
+	configTests := []configTest{
+		{name: "Test_wc_total_order", plugin: "wc.so", coordinatorFlags: []string{"-total-order"}, totalOrder: true},
+		{name: "Test_wc_split_size", plugin: "wc.so", coordinatorFlags: []string{"-split-size", "16"}},
+	}
+	for _, test := range configTests {
+		result := tr.runConfigTest(test)
+		results = append(results, result)
+		fmt.Println()
+	}
+
 	// Test especial para wc_with_fails.so
 	failureResult := tr.runFailureTest()
 
@@ -0,0 +1,85 @@
+package attempt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const outputMode = 0644
+
+type File struct {
+	*os.File
+	finalPath string
+}
+
+// Outputs agrupa los archivos que escribe un intento de una tarea. Se escriben en archivos temporales propios del
+// intento y solo aparecen con su nombre final al hacer Commit, así un intento que muere a mitad de camino (o un
+// duplicado) nunca deja archivos truncados o mezclados.
+type Outputs struct {
+	attempt int32
+	files   []*File
+}
+
+func NewOutputs(attempt int32) *Outputs {
+	return &Outputs{attempt: attempt}
+}
+
+func (o *Outputs) Create(finalPath string) (*File, error) {
+	dir, name := filepath.Split(finalPath)
+	if dir == "" {
+		dir = "."
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio %s: %v", dir, err)
+	}
+
+	file, err := os.CreateTemp(dir, fmt.Sprintf(".%s.attempt-%d-*", name, o.attempt))
+	if err != nil {
+		return nil, fmt.Errorf("error creando archivo temporal para %s: %v", finalPath, err)
+	}
+	// CreateTemp crea los archivos con modo 0600; las salidas quedan con los permisos habituales de os.Create
+	if err := file.Chmod(outputMode); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("error ajustando los permisos de %s: %v", file.Name(), err)
+	}
+
+	attemptFile := &File{File: file, finalPath: finalPath}
+	o.files = append(o.files, attemptFile)
+
+	return attemptFile, nil
+}
+
+func (o *Outputs) Commit() error {
+	for _, file := range o.files {
+		if err := file.Sync(); err != nil {
+			o.Discard()
+			return fmt.Errorf("error sincronizando %s: %v", file.Name(), err)
+		}
+		if err := file.Close(); err != nil {
+			o.Discard()
+			return fmt.Errorf("error cerrando %s: %v", file.Name(), err)
+		}
+	}
+
+	for _, file := range o.files {
+		if err := os.Rename(file.Name(), file.finalPath); err != nil {
+			o.Discard()
+			return fmt.Errorf("error confirmando %s: %v", file.finalPath, err)
+		}
+	}
+
+	o.files = nil
+	return nil
+}
+
+func (o *Outputs) Discard() {
+	for _, file := range o.files {
+		file.Close()
+		os.Remove(file.Name())
+	}
+
+	o.files = nil
+}
@@ -0,0 +1,204 @@
+package extsort
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+	"os"
+	"tp1/mr"
+	"tp1/pkg/intermediate"
+)
+
+// Merger mezcla runs ordenados por clave sin cargarlos en memoria. Si hay más runs de los que entran abiertos a la
+// vez en el presupuesto de memoria, primero los mezcla por tandas en runs temporales en disco (merge sort externo).
+type Merger struct {
+	tempDir   string
+	encoding  intermediate.Encoding
+	fanIn     int
+	tempFiles []string
+}
+
+// NewMerger crea un Merger que escribe sus runs temporales en tempDir con el encoding intermedio indicado.
+func NewMerger(tempDir string, memoryBudget int64, encoding intermediate.Encoding) *Merger {
+	return &Merger{tempDir: tempDir, encoding: encoding, fanIn: max(2, int(memoryBudget/readBufferSize))}
+}
+
+// Merge devuelve un iterador sobre todos los pares de los runs, ordenados por clave.
+func (m *Merger) Merge(runs []string) (*Iterator, error) {
+	runs = append([]string(nil), runs...)
+	for len(runs) > m.fanIn {
+		spilled, err := m.spill(runs[:m.fanIn])
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs[m.fanIn:], spilled)
+	}
+
+	return openIterator(runs)
+}
+
+// Cleanup borra los runs temporales que se escribieron durante la mezcla.
+func (m *Merger) Cleanup() {
+	for _, tempFile := range m.tempFiles {
+		os.Remove(tempFile)
+	}
+	m.tempFiles = nil
+}
+
+func (m *Merger) spill(runs []string) (string, error) {
+	if err := os.MkdirAll(m.tempDir, 0755); err != nil {
+		return "", fmt.Errorf("error creando directorio %s: %v", m.tempDir, err)
+	}
+
+	file, err := os.CreateTemp(m.tempDir, ".merge-run-*")
+	if err != nil {
+		return "", fmt.Errorf("error creando run temporal: %v", err)
+	}
+	defer file.Close()
+	m.tempFiles = append(m.tempFiles, file.Name())
+
+	iterator, err := openIterator(runs)
+	if err != nil {
+		return "", err
+	}
+	defer iterator.Close()
+
+	writer, err := intermediate.NewWriter(file, m.encoding)
+	if err != nil {
+		return "", fmt.Errorf("error escribiendo run temporal %s: %v", file.Name(), err)
+	}
+	for {
+		kv, err := iterator.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if err := writer.Write(kv); err != nil {
+			return "", fmt.Errorf("error escribiendo run temporal %s: %v", file.Name(), err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("error escribiendo run temporal %s: %v", file.Name(), err)
+	}
+
+	return file.Name(), nil
+}
+
+type heapItem struct {
+	kv     mr.KeyValue
+	source int
+}
+
+type mergeHeap []heapItem
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	if h[i].kv.Key != h[j].kv.Key {
+		return h[i].kv.Key < h[j].kv.Key
+	}
+	return h[i].source < h[j].source
+}
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x any)   { *h = append(*h, x.(heapItem)) }
+func (h *mergeHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// Iterator recorre en orden la mezcla de varios runs.
+type Iterator struct {
+	readers []*Reader
+	heap    mergeHeap
+}
+
+func openIterator(runs []string) (*Iterator, error) {
+	iterator := &Iterator{}
+
+	for _, run := range runs {
+		reader, err := OpenReader(run)
+		if err != nil {
+			iterator.Close()
+			return nil, err
+		}
+		iterator.readers = append(iterator.readers, reader)
+
+		if err := iterator.advance(len(iterator.readers) - 1); err != nil {
+			iterator.Close()
+			return nil, err
+		}
+	}
+
+	return iterator, nil
+}
+
+func (it *Iterator) advance(source int) error {
+	kv, err := it.readers[source].Next()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	heap.Push(&it.heap, heapItem{kv: kv, source: source})
+	return nil
+}
+
+// Next devuelve el siguiente par en orden, o io.EOF cuando se terminan todos los runs.
+func (it *Iterator) Next() (mr.KeyValue, error) {
+	if it.heap.Len() == 0 {
+		return mr.KeyValue{}, io.EOF
+	}
+
+	item := heap.Pop(&it.heap).(heapItem)
+	if err := it.advance(item.source); err != nil {
+		return mr.KeyValue{}, err
+	}
+
+	return item.kv, nil
+}
+
+func (it *Iterator) Close() {
+	for _, reader := range it.readers {
+		reader.Close()
+	}
+	it.readers = nil
+}
+
+// ForEachKey agrupa los pares consecutivos con la misma clave y llama a fn una vez por clave. Solo se mantienen en
+// memoria los valores de la clave actual.
+func ForEachKey(iterator *Iterator, fn func(key string, values []string) error) error {
+	var currentKey string
+	var values []string
+
+	for {
+		kv, err := iterator.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if values != nil && kv.Key != currentKey {
+			if err := fn(currentKey, values); err != nil {
+				return err
+			}
+			values = nil
+		}
+
+		currentKey = kv.Key
+		values = append(values, kv.Value)
+	}
+
+	if values != nil {
+		return fn(currentKey, values)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package extsort
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"tp1/mr"
+	"tp1/pkg/intermediate"
+)
+
+var testEncoding = intermediate.Encoding{Format: intermediate.FormatJSONL, Codec: intermediate.CodecNone}
+
+func writeRun(t *testing.T, dir string, name string, kvs []mr.KeyValue) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer, err := intermediate.NewWriter(file, testEncoding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kv := range kvs {
+		if err := writer.Write(kv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func readAll(t *testing.T, iterator *Iterator) []mr.KeyValue {
+	t.Helper()
+
+	var kvs []mr.KeyValue
+	for {
+		kv, err := iterator.Next()
+		if err == io.EOF {
+			return kvs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		kvs = append(kvs, kv)
+	}
+}
+
+func sortedKeyValues(kvs []mr.KeyValue) []string {
+	lines := make([]string, len(kvs))
+	for i, kv := range kvs {
+		lines[i] = kv.Key + " " + kv.Value
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestMergerMerge(t *testing.T) {
+	tests := []struct {
+		name         string
+		runs         int
+		pairsPerRun  int
+		memoryBudget int64
+		wantSpills   int
+	}{
+		{name: "un solo run", runs: 1, pairsPerRun: 10, memoryBudget: 64 << 20},
+		{name: "runs que entran en el fan-in", runs: 5, pairsPerRun: 10, memoryBudget: 64 << 20},
+		{name: "runs vacíos", runs: 3, pairsPerRun: 0, memoryBudget: 64 << 20},
+		{name: "un run más que el fan-in", runs: 3, pairsPerRun: 10, memoryBudget: 2 * readBufferSize, wantSpills: 1},
+		{name: "muchos más runs que el fan-in", runs: 9, pairsPerRun: 7, memoryBudget: 3 * readBufferSize, wantSpills: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			var runs []string
+			var all []mr.KeyValue
+			for r := 0; r < tt.runs; r++ {
+				var kvs []mr.KeyValue
+				for i := 0; i < tt.pairsPerRun; i++ {
+					// Claves repetidas entre runs y dentro de un mismo run
+					kvs = append(kvs, mr.KeyValue{Key: fmt.Sprintf("clave-%02d", (i*tt.runs+r)/2), Value: fmt.Sprintf("%d-%d", r, i)})
+				}
+				sort.SliceStable(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+				runs = append(runs, writeRun(t, dir, fmt.Sprintf("run-%d", r), kvs))
+				all = append(all, kvs...)
+			}
+
+			merger := NewMerger(filepath.Join(dir, "tmp"), tt.memoryBudget, testEncoding)
+			iterator, err := merger.Merge(runs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			merged := readAll(t, iterator)
+			iterator.Close()
+
+			if len(merger.tempFiles) != tt.wantSpills {
+				t.Errorf("se escribieron %d runs temporales, se esperaban %d", len(merger.tempFiles), tt.wantSpills)
+			}
+			if !sort.SliceIsSorted(merged, func(i, j int) bool { return merged[i].Key < merged[j].Key }) {
+				t.Errorf("la mezcla no quedó ordenada por clave: %v", merged)
+			}
+			if got, want := sortedKeyValues(merged), sortedKeyValues(all); strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("la mezcla no tiene los mismos pares que los runs:\ngot  %v\nwant %v", got, want)
+			}
+
+			merger.Cleanup()
+			leftovers, _ := filepath.Glob(filepath.Join(dir, "tmp", ".merge-run-*"))
+			if len(leftovers) != 0 {
+				t.Errorf("quedaron runs temporales en disco: %v", leftovers)
+			}
+		})
+	}
+}
+
+func TestMergerRejectsUnsortedRun(t *testing.T) {
+	dir := t.TempDir()
+	run := writeRun(t, dir, "run", []mr.KeyValue{{Key: "b", Value: "1"}, {Key: "a", Value: "1"}})
+
+	merger := NewMerger(dir, 64<<20, testEncoding)
+	iterator, err := merger.Merge([]string{run})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iterator.Close()
+
+	for {
+		_, err := iterator.Next()
+		if err == io.EOF {
+			t.Fatal("se esperaba un error por el run desordenado")
+		}
+		if err != nil {
+			if !strings.Contains(err.Error(), "no está ordenado") {
+				t.Errorf("error inesperado: %v", err)
+			}
+			return
+		}
+	}
+}
+
+func TestForEachKey(t *testing.T) {
+	dir := t.TempDir()
+	first := writeRun(t, dir, "first", []mr.KeyValue{{Key: "a", Value: "1"}, {Key: "a", Value: "2"}, {Key: "c", Value: "3"}})
+	second := writeRun(t, dir, "second", []mr.KeyValue{{Key: "a", Value: "4"}, {Key: "b", Value: "5"}})
+
+	merger := NewMerger(dir, 64<<20, testEncoding)
+	iterator, err := merger.Merge([]string{first, second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iterator.Close()
+
+	var groups []string
+	err = ForEachKey(iterator, func(key string, values []string) error {
+		groups = append(groups, key+"="+strings.Join(values, ","))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a=1,2,4", "b=5", "c=3"}
+	if strings.Join(groups, " ") != strings.Join(want, " ") {
+		t.Errorf("ForEachKey agrupó %v, se esperaba %v", groups, want)
+	}
+}
@@ -0,0 +1,60 @@
+package extsort
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"tp1/mr"
+	"tp1/pkg/intermediate"
+)
+
+// readBufferSize es el buffer de lectura de cada run abierto; determina cuántos runs entran en el presupuesto de
+// memoria al mezclar.
+const readBufferSize = 64 * 1024
+
+// Reader lee en orden los pares de un run intermedio y verifica que vengan ordenados por clave, que es lo que
+// necesita la mezcla.
+type Reader struct {
+	path    string
+	file    *os.File
+	reader  *intermediate.Reader
+	lastKey *string
+}
+
+func OpenReader(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo run %s: %v", path, err)
+	}
+
+	reader, err := intermediate.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error leyendo run %s: %v", path, err)
+	}
+
+	return &Reader{path: path, file: file, reader: reader}, nil
+}
+
+// Next devuelve el siguiente par del run, o io.EOF cuando se termina.
+func (r *Reader) Next() (mr.KeyValue, error) {
+	kv, err := r.reader.Next()
+	if err == io.EOF {
+		return mr.KeyValue{}, io.EOF
+	}
+	if err != nil {
+		return mr.KeyValue{}, fmt.Errorf("error leyendo run %s: %v", r.path, err)
+	}
+
+	if r.lastKey != nil && kv.Key < *r.lastKey {
+		return mr.KeyValue{}, fmt.Errorf("el run %s no está ordenado: %q aparece después de %q", r.path, kv.Key, *r.lastKey)
+	}
+	r.lastKey = &kv.Key
+
+	return kv, nil
+}
+
+func (r *Reader) Close() error {
+	r.reader.Close()
+	return r.file.Close()
+}
@@ -0,0 +1,54 @@
+package jobconfig
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	pb "tp1/protocol/messages"
+)
+
+// Cache guarda la configuración de cada job que el worker ya pidió al coordinator, así todas las tareas de un mismo
+// job usan la misma cantidad de reducers, plugin y formato sin volver a preguntarlos en cada asignación. Si el job se
+// vuelve a enviar (otra generación con el mismo id) la configuración se pide de nuevo.
+type Cache struct {
+	client  pb.ServerClient
+	mutex   sync.Mutex
+	configs map[string]*pb.JobConfig
+}
+
+func NewCache(client pb.ServerClient) *Cache {
+	return &Cache{client: client, configs: make(map[string]*pb.JobConfig)}
+}
+
+func (c *Cache) Get(jobId string, generation int32) (*pb.JobConfig, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if config, ok := c.configs[jobId]; ok && config.Generation == generation {
+		return config, nil
+	}
+
+	config, err := c.client.GetJobConfig(context.Background(), &pb.JobConfigRequest{JobId: jobId})
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(config); err != nil {
+		return nil, fmt.Errorf("configuración inválida del job %s: %v", jobId, err)
+	}
+	if config.Generation != generation {
+		return nil, fmt.Errorf("el job %s ya se volvió a enviar (generación %d, la tarea es de la %d)", jobId, config.Generation, generation)
+	}
+
+	c.configs[jobId] = config
+	return config, nil
+}
+
+func validate(config *pb.JobConfig) error {
+	if config.ReducerNumber <= 0 {
+		return fmt.Errorf("cantidad de reducers %d", config.ReducerNumber)
+	}
+	if config.MapNumber <= 0 {
+		return fmt.Errorf("cantidad de maps %d", config.MapNumber)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package shuffle
+
+import (
+	"fmt"
+	pb "tp1/protocol/messages"
+)
+
+// MissingOutputsError indica que no se pudieron obtener particiones de maps que el coordinator dio por confirmados,
+// por ejemplo porque el worker que las tenía ya no está o se borraron de su directorio intermedio. El worker se los
+// reporta al coordinator para que vuelva a ejecutar esos maps.
+type MissingOutputsError struct {
+	Missing []*pb.MapOutput
+}
+
+func (e *MissingOutputsError) Error() string {
+	ids := make([]int32, len(e.Missing))
+	for i, mapOutput := range e.Missing {
+		ids[i] = mapOutput.MapTaskId
+	}
+	return fmt.Sprintf("faltan las salidas de los maps %v", ids)
+}
+
+// Check verifica que las salidas de maps de un reduce cubran exactamente los maps 1..nMapTasks, salvo los omitidos
+// por cuarentena.
+func Check(mapOutputs []*pb.MapOutput, skippedMapTaskIds []int32, nMapTasks int32) error {
+	covered := make([]bool, nMapTasks+1)
+	for _, mapTaskId := range skippedMapTaskIds {
+		if mapTaskId < 1 || mapTaskId > nMapTasks {
+			return fmt.Errorf("map omitido %d fuera del rango [1, %d]", mapTaskId, nMapTasks)
+		}
+		covered[mapTaskId] = true
+	}
+
+	for _, mapOutput := range mapOutputs {
+		if mapOutput.MapTaskId < 1 || mapOutput.MapTaskId > nMapTasks {
+			return fmt.Errorf("salida del map %d fuera del rango [1, %d]", mapOutput.MapTaskId, nMapTasks)
+		}
+		if covered[mapOutput.MapTaskId] {
+			return fmt.Errorf("el map %d aparece más de una vez", mapOutput.MapTaskId)
+		}
+		covered[mapOutput.MapTaskId] = true
+	}
+
+	for mapTaskId := int32(1); mapTaskId <= nMapTasks; mapTaskId++ {
+		if !covered[mapTaskId] {
+			return fmt.Errorf("el coordinator no indicó la salida del map %d de %d", mapTaskId, nMapTasks)
+		}
+	}
+
+	return nil
+}
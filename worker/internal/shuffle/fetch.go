@@ -0,0 +1,208 @@
+package shuffle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"tp1/pkg/intermediate"
+	"tp1/pkg/transport"
+
+	pb "tp1/protocol/messages"
+
+	"google.golang.org/grpc"
+)
+
+// Fetcher trae las particiones que necesita un reduce desde los workers que ejecutaron cada map. Reutiliza una
+// conexión por worker entre tareas.
+type Fetcher struct {
+	dialOption grpc.DialOption
+	retries    int
+	retryDelay time.Duration
+	mutex      sync.Mutex
+	conns      map[string]*grpc.ClientConn
+}
+
+func NewFetcher(dialOption grpc.DialOption, retries int, retryDelay time.Duration) *Fetcher {
+	return &Fetcher{dialOption: dialOption, retries: retries, retryDelay: retryDelay, conns: make(map[string]*grpc.ClientConn)}
+}
+
+func (f *Fetcher) Close() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+	f.conns = make(map[string]*grpc.ClientConn)
+}
+
+// Download son las particiones de un reduce ya disponibles localmente.
+type Download struct {
+	Files   []string
+	tempDir string
+}
+
+// Cleanup borra las particiones descargadas. Las que se leen directo del directorio compartido no se tocan.
+func (d *Download) Cleanup() {
+	os.RemoveAll(d.tempDir)
+}
+
+// Fetch trae la partición partition de cada salida de map a un directorio temporal dentro de intermediateDir, el
+// directorio intermedio del job. locations son las direcciones de los workers a las que apunta el LocationIndex de
+// cada salida. Las salidas sin ubicación (registradas antes de que existiera el shuffle por gRPC) o cuyo worker no
+// responde pero que se ven en intermediateDir (porque los workers lo comparten) se leen directo de ahí. Si alguna no
+// se puede obtener después de los reintentos devuelve un *MissingOutputsError.
+func (f *Fetcher) Fetch(jobId string, partition int32, mapOutputs []*pb.MapOutput, locations []string, intermediateDir string) (*Download, error) {
+	for _, mapOutput := range mapOutputs {
+		if mapOutput.LocationIndex < 0 || int(mapOutput.LocationIndex) > len(locations) {
+			return nil, fmt.Errorf("la salida del map %d indica la ubicación %d de %d", mapOutput.MapTaskId,
+				mapOutput.LocationIndex, len(locations))
+		}
+	}
+
+	if err := os.MkdirAll(intermediateDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creando directorio %s: %v", intermediateDir, err)
+	}
+	downloadDir, err := os.MkdirTemp(intermediateDir, ".shuffle-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creando directorio temporal en %s: %v", intermediateDir, err)
+	}
+	download := &Download{Files: make([]string, len(mapOutputs)), tempDir: downloadDir}
+
+	pending := make([]int, len(mapOutputs))
+	for i := range mapOutputs {
+		pending[i] = i
+	}
+
+	for retry := 0; ; retry++ {
+		var failed []int
+		for _, i := range pending {
+			path, err := f.fetchOne(jobId, partition, mapOutputs[i], locations, intermediateDir, downloadDir)
+			var writeErr *localWriteError
+			if errors.As(err, &writeErr) {
+				download.Cleanup()
+				return nil, writeErr.err
+			}
+			if err != nil {
+				log.Printf("No se pudo obtener la partición %d del map %d: %v", partition, mapOutputs[i].MapTaskId, err)
+				failed = append(failed, i)
+				continue
+			}
+			download.Files[i] = path
+		}
+
+		if len(failed) == 0 {
+			return download, nil
+		}
+		if retry == f.retries {
+			missing := make([]*pb.MapOutput, len(failed))
+			for j, i := range failed {
+				missing[j] = mapOutputs[i]
+			}
+			download.Cleanup()
+			return nil, &MissingOutputsError{Missing: missing}
+		}
+
+		pending = failed
+		time.Sleep(f.retryDelay)
+	}
+}
+
+func (f *Fetcher) fetchOne(jobId string, partition int32, mapOutput *pb.MapOutput, locations []string, intermediateDir string, downloadDir string) (string, error) {
+	localPath := filepath.Join(intermediateDir, intermediate.MapOutputName(int(mapOutput.MapTaskId),
+		int(mapOutput.Attempt), int(partition)))
+	if mapOutput.LocationIndex == 0 {
+		if _, err := os.Stat(localPath); err != nil {
+			return "", err
+		}
+		return localPath, nil
+	}
+
+	location := locations[mapOutput.LocationIndex-1]
+	path, err := f.fetchRemote(jobId, partition, mapOutput, location, downloadDir)
+	var writeErr *localWriteError
+	if err != nil && !errors.As(err, &writeErr) {
+		if _, statErr := os.Stat(localPath); statErr == nil {
+			log.Printf("El worker %s no sirvió la partición %d del map %d (%v), se lee de %s", location,
+				partition, mapOutput.MapTaskId, err, localPath)
+			return localPath, nil
+		}
+	}
+	return path, err
+}
+
+func (f *Fetcher) fetchRemote(jobId string, partition int32, mapOutput *pb.MapOutput, location string, downloadDir string) (string, error) {
+	conn, err := f.conn(location)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := pb.NewShuffleClient(conn).FetchPartition(ctx, &pb.PartitionRequest{JobId: jobId,
+		MapTaskId: mapOutput.MapTaskId, Attempt: mapOutput.Attempt, Partition: partition})
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(downloadDir, fmt.Sprintf("mr-%d-%d-*", mapOutput.MapTaskId, partition))
+	if err != nil {
+		return "", &localWriteError{fmt.Errorf("error creando archivo temporal en %s: %v", downloadDir, err)}
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return "", err
+		}
+		if _, err := file.Write(chunk.Data); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return "", &localWriteError{fmt.Errorf("error escribiendo %s: %v", file.Name(), err)}
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", &localWriteError{fmt.Errorf("error escribiendo %s: %v", file.Name(), err)}
+	}
+	return file.Name(), nil
+}
+
+// localWriteError es un error guardando una partición en este worker: no es culpa del map, así que no se reintenta
+// ni se reporta como salida perdida.
+type localWriteError struct {
+	err error
+}
+
+func (e *localWriteError) Error() string {
+	return e.err.Error()
+}
+
+func (f *Fetcher) conn(address string) (*grpc.ClientConn, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if conn, ok := f.conns[address]; ok {
+		return conn, nil
+	}
+
+	conn, err := grpc.Dial(transport.DialTarget(address), f.dialOption)
+	if err != nil {
+		return nil, err
+	}
+	f.conns[address] = conn
+	return conn, nil
+}
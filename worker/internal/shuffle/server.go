@@ -0,0 +1,81 @@
+package shuffle
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"tp1/pkg/intermediate"
+	"tp1/pkg/transport"
+
+	pb "tp1/protocol/messages"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const chunkSize = 64 * 1024
+
+// server sirve las particiones que escribieron los maps de este worker en su directorio intermedio local.
+type server struct {
+	pb.UnimplementedShuffleServer
+	intermediateDir string
+}
+
+// Serve empieza a servir en address las particiones guardadas en intermediateDir y devuelve la función que detiene
+// el servidor.
+func Serve(address string, intermediateDir string, serverOptions []grpc.ServerOption) (func(), error) {
+	lis, err := transport.Listen(address)
+	if err != nil {
+		return nil, fmt.Errorf("error escuchando en %s: %v", address, err)
+	}
+
+	grpcServer := grpc.NewServer(serverOptions...)
+	pb.RegisterShuffleServer(grpcServer, &server{intermediateDir: intermediateDir})
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("El servidor de particiones se detuvo: %v", err)
+		}
+	}()
+
+	return func() {
+		grpcServer.Stop()
+		transport.Cleanup(address)
+	}, nil
+}
+
+func (s *server) FetchPartition(req *pb.PartitionRequest, stream pb.Shuffle_FetchPartitionServer) error {
+	// El id del job se usa como nombre de directorio: no puede salirse del directorio intermedio
+	if req.JobId == "" || req.JobId != filepath.Base(req.JobId) || req.JobId == "." || req.JobId == ".." {
+		return status.Errorf(codes.InvalidArgument, "job inválido %q", req.JobId)
+	}
+
+	name := intermediate.MapOutputName(int(req.MapTaskId), int(req.Attempt), int(req.Partition))
+	file, err := os.Open(filepath.Join(s.intermediateDir, req.JobId, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return status.Errorf(codes.NotFound, "no tengo la partición %s del job %s", name, req.JobId)
+		}
+		return status.Errorf(codes.Internal, "error abriendo la partición %s: %v", name, err)
+	}
+	defer file.Close()
+
+	buffer := make([]byte, chunkSize)
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			if err := stream.Send(&pb.PartitionChunk{Data: buffer[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "error leyendo la partición %s: %v", name, err)
+		}
+	}
+}
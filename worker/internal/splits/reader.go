@@ -0,0 +1,62 @@
+package splits
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Read devuelve las líneas del archivo que empiezan dentro del rango [offset, offset+length). La última línea se lee
+// completa aunque termine después del rango y la línea que empezó antes del rango se saltea, porque la lee el split
+// anterior. Con length 0 devuelve el archivo completo.
+func Read(path string, offset int64, length int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error leyendo archivo %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if offset == 0 && length == 0 {
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return "", fmt.Errorf("error leyendo archivo %s: %v", path, err)
+		}
+		return string(content), nil
+	}
+
+	// Arranco un byte antes para saber si offset cae justo al principio de una línea
+	position := max(offset-1, 0)
+	if _, err := file.Seek(position, io.SeekStart); err != nil {
+		return "", fmt.Errorf("error leyendo archivo %s: %v", path, err)
+	}
+	reader := bufio.NewReader(file)
+
+	if offset > 0 {
+		skipped, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("error leyendo archivo %s: %v", path, err)
+		}
+		position += int64(len(skipped))
+	}
+
+	end := offset + length
+	var content []byte
+	for position < end {
+		line, err := reader.ReadBytes('\n')
+		content = append(content, line...)
+		position += int64(len(line))
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("error leyendo archivo %s: %v", path, err)
+		}
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,104 @@
+package splits
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "entrada.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRead(t *testing.T) {
+	const content = "uno\ndos\ntres\n"
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+	}{
+		{name: "archivo completo", offset: 0, length: 0, want: content},
+		{name: "rango que termina justo en un salto de línea", offset: 0, length: 4, want: "uno\n"},
+		{name: "la última línea se lee completa aunque cruce el final", offset: 0, length: 5, want: "uno\ndos\n"},
+		{name: "rango que empieza justo al principio de una línea", offset: 4, length: 4, want: "dos\n"},
+		{name: "se saltea la línea que empezó antes del rango", offset: 5, length: 4, want: "tres\n"},
+		{name: "rango dentro de una sola línea que empezó antes", offset: 9, length: 2, want: ""},
+		{name: "rango que empieza en el salto de línea", offset: 3, length: 1, want: ""},
+		{name: "rango de un byte al principio de una línea", offset: 8, length: 1, want: "tres\n"},
+		{name: "offset en el final del archivo", offset: int64(len(content)), length: 10, want: ""},
+		{name: "offset después del final del archivo", offset: int64(len(content)) + 5, length: 10, want: ""},
+	}
+
+	path := writeFile(t, content)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(path, tt.offset, tt.length)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Read(%d, %d) = %q, se esperaba %q", tt.offset, tt.length, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadWithoutTrailingNewline(t *testing.T) {
+	path := writeFile(t, "uno\ndos")
+
+	if got, err := Read(path, 0, 5); err != nil || got != "uno\ndos" {
+		t.Errorf("Read(0, 5) = %q, %v; se esperaba la última línea completa", got, err)
+	}
+	if got, err := Read(path, 7, 3); err != nil || got != "" {
+		t.Errorf("Read en el final del archivo = %q, %v; se esperaba vacío", got, err)
+	}
+	if got, err := Read(path, 5, 3); err != nil || got != "" {
+		t.Errorf("Read dentro de la última línea = %q, %v; se esperaba vacío", got, err)
+	}
+}
+
+// Sin importar dónde caigan los cortes, cada línea tiene que leerla exactamente un split.
+func TestReadSplitsCoverEveryLineOnce(t *testing.T) {
+	contents := []string{
+		"uno\ndos\ntres\n",
+		"sin salto final\ny otra línea",
+		"\n\nlíneas\n\nvacías\n\n",
+		"una línea bastante más larga que los splits más chicos\ncorta\n",
+		"x",
+		"",
+	}
+
+	for _, content := range contents {
+		path := writeFile(t, content)
+		size := int64(len(content))
+
+		for splitSize := int64(1); splitSize <= size+1; splitSize++ {
+			var joined string
+			for offset := int64(0); offset < size; offset += splitSize {
+				part, err := Read(path, offset, min(splitSize, size-offset))
+				if err != nil {
+					t.Fatal(err)
+				}
+				joined += part
+			}
+
+			if joined != content {
+				t.Errorf("con splits de %d bytes se leyó %q, se esperaba %q", splitSize, joined, content)
+			}
+		}
+	}
+}
+
+func TestReadMissingFile(t *testing.T) {
+	if _, err := Read(filepath.Join(t.TempDir(), "no-existe.txt"), 0, 0); err == nil {
+		t.Error("se esperaba un error con un archivo inexistente")
+	}
+}
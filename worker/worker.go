@@ -1,17 +1,27 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"hash/fnv"
-	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
 	"plugin"
+	"sort"
 	"strings"
 	"time"
 	"tp1/mr"
+	"tp1/pkg/intermediate"
+	"tp1/pkg/transport"
+	"tp1/worker/internal/attempt"
+	"tp1/worker/internal/extsort"
+	"tp1/worker/internal/jobconfig"
+	"tp1/worker/internal/shuffle"
+	"tp1/worker/internal/splits"
 
 	"github.com/google/uuid"
 
@@ -20,26 +30,108 @@ import (
 	"google.golang.org/grpc"
 )
 
-func loadPlugin(pluginPath string) (func(string, string) []mr.KeyValue, func(string, []string) string, error) {
+// combineF y partitionF son opcionales: si el plugin exporta Combine, los maps preagregan cada partición antes de
+// escribirla; si exporta Partition, la usan en vez de ihash para elegir el reducer de cada clave.
+type mrPlugin struct {
+	mapF       func(string, string) []mr.KeyValue
+	reduceF    func(string, []string) string
+	combineF   func(string, []string) []string
+	partitionF func(string, int) int
+}
+
+// Plugins ya cargados, indexados por ruta, para no volver a buscar sus símbolos en cada tarea.
+var loadedPlugins = make(map[string]*mrPlugin)
+
+func loadPlugin(pluginPath string) (*mrPlugin, error) {
+	if loaded, exists := loadedPlugins[pluginPath]; exists {
+		return loaded, nil
+	}
+
 	plug, err := plugin.Open(pluginPath)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error abriendo plugin %s: %v", pluginPath, err)
+		return nil, fmt.Errorf("error abriendo plugin %s: %v", pluginPath, err)
 	}
 
 	mapFunc, err := plug.Lookup("Map")
 	if err != nil {
-		return nil, nil, fmt.Errorf("error encontrando función Map: %v", err)
+		return nil, fmt.Errorf("error encontrando función Map: %v", err)
 	}
 
 	reduceFunc, err := plug.Lookup("Reduce")
 	if err != nil {
-		return nil, nil, fmt.Errorf("error encontrando función Reduce: %v", err)
+		return nil, fmt.Errorf("error encontrando función Reduce: %v", err)
+	}
+
+	mapF, ok := mapFunc.(func(string, string) []mr.KeyValue)
+	if !ok {
+		return nil, fmt.Errorf("la función Map de %s no tiene la firma esperada", pluginPath)
+	}
+
+	reduceF, ok := reduceFunc.(func(string, []string) string)
+	if !ok {
+		return nil, fmt.Errorf("la función Reduce de %s no tiene la firma esperada", pluginPath)
+	}
+
+	loaded := &mrPlugin{mapF: mapF, reduceF: reduceF}
+
+	if combineFunc, err := plug.Lookup("Combine"); err == nil {
+		combineF, ok := combineFunc.(func(string, []string) []string)
+		if !ok {
+			return nil, fmt.Errorf("la función Combine de %s no tiene la firma esperada", pluginPath)
+		}
+		loaded.combineF = combineF
+	}
+
+	if partitionFunc, err := plug.Lookup("Partition"); err == nil {
+		partitionF, ok := partitionFunc.(func(string, int) int)
+		if !ok {
+			return nil, fmt.Errorf("la función Partition de %s no tiene la firma esperada", pluginPath)
+		}
+		loaded.partitionF = partitionF
+	}
+
+	loadedPlugins[pluginPath] = loaded
+	log.Printf("Plugin cargado: %s", pluginPath)
+
+	return loaded, nil
+}
+
+func resolvePluginPath(pluginsDir string, pluginName string) string {
+	if !strings.HasSuffix(pluginName, ".so") {
+		pluginName += ".so"
+	}
+	// Si no incluye la ruta, asumo que está en el directorio de plugins
+	if !strings.Contains(pluginName, "/") {
+		pluginName = filepath.Join(pluginsDir, pluginName)
+	}
+	return pluginName
+}
+
+// jobPluginPath resuelve el plugin que indica un job. Como cualquiera que envíe un job lo elige, solo se aceptan
+// nombres sin ruta, que siempre quedan dentro del directorio de plugins del worker.
+func jobPluginPath(pluginsDir string, pluginName string) (string, error) {
+	if filepath.Base(pluginName) != pluginName || pluginName == "." || pluginName == ".." {
+		return "", fmt.Errorf("el plugin %q del job no es un nombre dentro del directorio de plugins", pluginName)
 	}
+	return resolvePluginPath(pluginsDir, pluginName), nil
+}
 
-	mapF := mapFunc.(func(string, string) []mr.KeyValue)
-	reduceF := reduceFunc.(func(string, []string) string)
+func pluginForWork(jobConfig *pb.JobConfig, pluginsDir string, defaultPluginPath string) (*mrPlugin, error) {
+	if jobConfig.Plugin != "" {
+		pluginPath, err := jobPluginPath(pluginsDir, jobConfig.Plugin)
+		if err != nil {
+			return nil, err
+		}
+		return loadPlugin(pluginPath)
+	}
+	if defaultPluginPath == "" {
+		return nil, fmt.Errorf("el job %s no indica plugin y el worker no tiene uno por defecto", jobConfig.JobId)
+	}
+	return loadPlugin(defaultPluginPath)
+}
 
-	return mapF, reduceF, nil
+func intermediateEncoding(jobConfig *pb.JobConfig) intermediate.Encoding {
+	return intermediate.Encoding{Format: jobConfig.IntermediateFormat, Codec: jobConfig.IntermediateCodec}
 }
 
 func ihash(key string) int {
@@ -48,203 +140,525 @@ func ihash(key string) int {
 	return int(h.Sum32() & 0x7fffffff)
 }
 
-func executeMapTask(mapF func(string, string) []mr.KeyValue, filePath string, workerId int32, reducerNumber int32) error {
-	content, err := ioutil.ReadFile(filePath)
+// samplesPerTask es la cantidad máxima de claves que reporta cada tarea de muestreo en modo orden total.
+const samplesPerTask = 100
+
+// partitionFor elige el reducer de una clave: en modo orden total por el rango de claves en el que cae según los
+// límites calculados por el coordinator y si no con la función Partition del plugin o, si no tiene, por hash.
+func partitionFor(partitionF func(string, int) int, key string, reducerNumber int32, totalOrder bool, partitionBounds []string) (int, error) {
+	if totalOrder {
+		partition := sort.Search(len(partitionBounds), func(i int) bool {
+			return key < partitionBounds[i]
+		})
+		return min(partition, int(reducerNumber)-1), nil
+	}
+
+	if partitionF == nil {
+		return ihash(key) % int(reducerNumber), nil
+	}
+
+	partition := partitionF(key, int(reducerNumber))
+	if partition < 0 || partition >= int(reducerNumber) {
+		return 0, fmt.Errorf("la función Partition devolvió %d para la clave %q, fuera del rango [0, %d)", partition, key, reducerNumber)
+	}
+	return partition, nil
+}
+
+// executeSampleTask corre Map sobre la entrada y devuelve una muestra ordenada y equiespaciada de las claves que
+// produce, con la que el coordinator elige los límites de las particiones.
+func executeSampleTask(mapF func(string, string) []mr.KeyValue, inputSplits []*pb.InputSplit) ([]string, error) {
+	mapResult, err := mapInputSplits(mapF, inputSplits)
 	if err != nil {
-		return fmt.Errorf("error leyendo archivo %s: %v", filePath, err)
+		return nil, err
 	}
 
-	mapResult := mapF(filePath, string(content))
+	keys := make([]string, len(mapResult))
+	for i, kv := range mapResult {
+		keys[i] = kv.Key
+	}
+	sort.Strings(keys)
+
+	if len(keys) <= samplesPerTask {
+		return keys, nil
+	}
 
-	fmt.Printf("DEBUG: workerId=%d, reducerNumber=%d, mapResult length=%d\n",
-		workerId, reducerNumber, len(mapResult))
+	sample := make([]string, samplesPerTask)
+	for i := range sample {
+		sample[i] = keys[i*len(keys)/samplesPerTask]
+	}
+	return sample, nil
+}
+
+// combinePartition aplica Combine a cada grupo de pares consecutivos con la misma clave de una partición ya ordenada.
+func combinePartition(combineF func(string, []string) []string, partition []mr.KeyValue) []mr.KeyValue {
+	var combined []mr.KeyValue
+
+	for start := 0; start < len(partition); {
+		end := start
+		var values []string
+		for end < len(partition) && partition[end].Key == partition[start].Key {
+			values = append(values, partition[end].Value)
+			end++
+		}
+
+		for _, value := range combineF(partition[start].Key, values) {
+			combined = append(combined, mr.KeyValue{Key: partition[start].Key, Value: value})
+		}
+		start = end
+	}
+
+	return combined
+}
+
+// mapInputSplits llama a Map una vez por cada split de la tarea, con la ruta del archivo y solo las líneas del split.
+func mapInputSplits(mapF func(string, string) []mr.KeyValue, inputSplits []*pb.InputSplit) ([]mr.KeyValue, error) {
+	var mapResult []mr.KeyValue
+
+	for _, inputSplit := range inputSplits {
+		content, err := splits.Read(inputSplit.Path, inputSplit.Offset, inputSplit.Length)
+		if err != nil {
+			return nil, err
+		}
+		mapResult = append(mapResult, mapF(inputSplit.Path, content)...)
+	}
+
+	return mapResult, nil
+}
+
+func executeMapTask(mrPlug *mrPlugin, inputSplits []*pb.InputSplit, intermediateDir string, taskId int32, attemptNumber int32, reducerNumber int32, totalOrder bool, partitionBounds []string, encoding intermediate.Encoding, outputs *attempt.Outputs) error {
+	mapResult, err := mapInputSplits(mrPlug.mapF, inputSplits)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("DEBUG: taskId=%d, reducerNumber=%d, mapResult length=%d\n",
+		taskId, reducerNumber, len(mapResult))
 
 	if reducerNumber <= 0 {
 		return fmt.Errorf("reducerNumber debe ser mayor que 0, recibido: %d", reducerNumber)
 	}
 
-	tempFiles := make([]*os.File, reducerNumber)
+	tempFiles := make([]*attempt.File, reducerNumber)
 	for i := int32(0); i < reducerNumber; i++ {
-		tempFileName := fmt.Sprintf("intermediate/mr-%d-%d", workerId, i+1)
-		tempFiles[i], err = os.Create(tempFileName)
+		name := intermediate.MapOutputName(int(taskId), int(attemptNumber), int(i+1))
+		tempFiles[i], err = outputs.Create(filepath.Join(intermediateDir, name))
 		if err != nil {
-			return fmt.Errorf("error creando archivo temporal %s: %v", tempFileName, err)
+			return err
 		}
-		defer tempFiles[i].Close()
 	}
 
+	// Cada partición se escribe ordenada por clave para que el reducer pueda mezclarlas sin cargarlas en memoria
+	partitions := make([][]mr.KeyValue, reducerNumber)
 	for _, kv := range mapResult {
-		hashValue := ihash(kv.Key)
-		reduceIndex := hashValue % int(reducerNumber)
+		reduceIndex, err := partitionFor(mrPlug.partitionF, kv.Key, reducerNumber, totalOrder, partitionBounds)
+		if err != nil {
+			return err
+		}
 
-		fmt.Printf("DEBUG: key='%s', hash=%d, reduceIndex=%d\n",
-			kv.Key, hashValue, reduceIndex)
+		fmt.Printf("DEBUG: key='%s', reduceIndex=%d\n",
+			kv.Key, reduceIndex)
 
-		_, err = tempFiles[reduceIndex].WriteString(fmt.Sprintf("%s %s\n", kv.Key, kv.Value))
+		partitions[reduceIndex] = append(partitions[reduceIndex], kv)
+	}
+
+	for i, partition := range partitions {
+		sort.SliceStable(partition, func(a, b int) bool {
+			return partition[a].Key < partition[b].Key
+		})
+		if mrPlug.combineF != nil {
+			partition = combinePartition(mrPlug.combineF, partition)
+		}
+
+		writer, err := intermediate.NewWriter(tempFiles[i], encoding)
 		if err != nil {
 			return fmt.Errorf("error escribiendo en archivo temporal: %v", err)
 		}
+		for _, kv := range partition {
+			if err := writer.Write(kv); err != nil {
+				return fmt.Errorf("error escribiendo en archivo temporal: %v", err)
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("error escribiendo en archivo temporal: %v", err)
+		}
 	}
 
 	return nil
 }
 
-func executeReduceTask(reduceF func(string, []string) string, reduceTaskId int32, nMapTasks int32) error {
+// Reintentos antes de dar por perdida la salida de un map, por si el worker que la tiene está momentáneamente
+// inaccesible.
+const mapOutputRetries = 3
+const mapOutputRetryDelay = time.Second
+
+// maxAssignmentSize es el tamaño máximo de un mensaje del coordinator. Un reduce recibe un elemento por map del job
+// y, en modo orden total, los límites de todas las particiones, así que puede pasar los 4 MB que gRPC acepta por
+// defecto.
+const maxAssignmentSize = 64 << 20
+
+// maxSortMemoryMB acota -sort-memory-mb para que el presupuesto en bytes no desborde un int64.
+const maxSortMemoryMB = 1 << 20
+
+// executeReduceTask lee solo las particiones que el coordinator registró como confirmadas, pidiéndoselas a los
+// workers que ejecutaron cada map, así archivos viejos o de otros intentos nunca se mezclan con el resultado.
+func executeReduceTask(reduceF func(string, []string) string, fetcher *shuffle.Fetcher, jobId string, mapOutputs []*pb.MapOutput, mapOutputLocations []string, skippedMapTaskIds []int32, intermediateDir string, outputPrefix string, reduceTaskId int32, nMapTasks int32, memoryBudget int64, encoding intermediate.Encoding, outputs *attempt.Outputs) error {
 
-	pattern := fmt.Sprintf("intermediate/mr-*-%d", reduceTaskId)
-	files, err := filepath.Glob(pattern)
+	if err := shuffle.Check(mapOutputs, skippedMapTaskIds, nMapTasks); err != nil {
+		return err
+	}
+
+	download, err := fetcher.Fetch(jobId, reduceTaskId, mapOutputs, mapOutputLocations, intermediateDir)
 	if err != nil {
-		return fmt.Errorf("error buscando archivos con patrón %s: %v", pattern, err)
+		return err
 	}
+	defer download.Cleanup()
+	files := download.Files
 
 	fmt.Printf("DEBUG: Encontrados %d archivos: %v\n", len(files), files)
 
-	var allKeyValues []mr.KeyValue
-
-	for _, filename := range files {
-		fmt.Printf("DEBUG: Leyendo archivo: %s\n", filename)
-
-		content, err := ioutil.ReadFile(filename)
-		if err != nil {
-			log.Printf("Error leyendo %s: %v", filename, err)
-			continue
-		}
+	merger := extsort.NewMerger(intermediateDir, memoryBudget, encoding)
+	defer merger.Cleanup()
 
-		keyValues := parseIntermediateFile(string(content))
-		allKeyValues = append(allKeyValues, keyValues...)
+	iterator, err := merger.Merge(files)
+	if err != nil {
+		return err
 	}
+	defer iterator.Close()
 
-	grouped := groupByKey(allKeyValues)
-
-	outputFile := fmt.Sprintf("output/mr-out-%d", reduceTaskId)
-	file, err := os.Create(outputFile)
+	file, err := outputs.Create(fmt.Sprintf("%s-%d", outputPrefix, reduceTaskId))
 	if err != nil {
 		return fmt.Errorf("error creando archivo de salida: %v", err)
 	}
-	defer file.Close()
 
-	for key, values := range grouped {
+	writer := bufio.NewWriter(file)
+	err = extsort.ForEachKey(iterator, func(key string, values []string) error {
 		result := reduceF(key, values)
-		file.WriteString(fmt.Sprintf("%s %s\n", key, result))
+		if _, err := fmt.Fprintf(writer, "%s %s\n", key, result); err != nil {
+			return fmt.Errorf("error escribiendo archivo de salida: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error escribiendo archivo de salida: %v", err)
 	}
 
 	return nil
 }
 
-func parseIntermediateFile(content string) []mr.KeyValue {
-	var keyValues []mr.KeyValue
-	lines := strings.Split(content, "\n")
-
-	for _, line := range lines {
-		if line == "" {
-			continue
+// runPluginTask ejecuta una tarea y convierte un panic del plugin (por ejemplo ante datos que no sabe interpretar) en
+// un error, así la tarea se reporta como fallida con el motivo en vez de tirar abajo el worker.
+func runPluginTask(task func() error) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic en el plugin: %v", recovered)
 		}
-		parts := strings.Split(line, " ")
-		if len(parts) == 2 {
-			keyValues = append(keyValues, mr.KeyValue{
-				Key:   parts[0],
-				Value: parts[1],
-			})
+	}()
+	return task()
+}
+
+func startHeartbeat(client pb.ServerClient, workerUuid string, work *pb.AskForWorkResponse, interval time.Duration) func() {
+	stop := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				resp, err := client.Heartbeat(context.Background(), &pb.ImAlive{WorkerUuid: workerUuid, WorkInProgress: work.FilePath,
+					WorkType: work.WorkType, JobId: work.JobId, Attempt: work.Attempt})
+				if err != nil {
+					log.Printf("Error enviando heartbeat: %v", err)
+					continue
+				}
+				if resp.Response != "OK" {
+					log.Printf("Worker %s - el coordinator ya no considera nuestra la tarea %s", workerUuid, work.FilePath)
+				}
+			}
 		}
+	}()
+
+	return func() { close(stop) }
+}
+
+// commitAndReport deja visibles los archivos del intento solo si el coordinator todavía nos considera dueños de la
+// tarea y nos concede el commit (si hay copias de respaldo en curso solo gana la primera) y después reporta la tarea
+// como terminada. Si el coordinator no responde, el próximo pedido de trabajo se encarga de esperarlo.
+func commitAndReport(client pb.ServerClient, workerUuid string, work *pb.AskForWorkResponse, outputs *attempt.Outputs, sampleKeys []string, shuffleAddress string) {
+	alive, err := client.Heartbeat(context.Background(), &pb.ImAlive{WorkerUuid: workerUuid, WorkInProgress: work.FilePath,
+		WorkType: work.WorkType, JobId: work.JobId, Attempt: work.Attempt, Committing: true})
+	if err != nil {
+		outputs.Discard()
+		log.Printf("Error verificando el lease de %s: %v", work.FilePath, err)
+		return
+	}
+	if alive.Response != "OK" {
+		outputs.Discard()
+		log.Printf("Descartando la salida de %s (intento %d): el coordinator ya no nos asigna la tarea o ya la confirmó otro intento", work.FilePath, work.Attempt)
+		return
+	}
+
+	if err := outputs.Commit(); err != nil {
+		log.Printf("Error confirmando la salida de %s: %v", work.FilePath, err)
+		return
+	}
+
+	finished, err := client.MarkWorkAsFinished(context.Background(), &pb.IFinished{WorkerUuid: workerUuid, WorkFinished: work.FilePath,
+		WorkType: work.WorkType, JobId: work.JobId, Attempt: work.Attempt, SampleKeys: sampleKeys, ShuffleAddress: shuffleAddress})
+	if err != nil {
+		log.Printf("Error marcando %s como terminado: %v", work.WorkType, err)
+		return
+	}
+	if !finished.Accepted {
+		log.Printf("El coordinator ignoró el %s %s (intento %d): %s", work.WorkType, work.FilePath, work.Attempt, finished.Response)
+	}
+}
+
+// reportFailure le avisa al coordinator que no pudimos completar la tarea para que la reasigne sin esperar a que
+// venza el lease. Si faltaban salidas de maps se las indica para que vuelva a ejecutarlos.
+func reportFailure(client pb.ServerClient, workerUuid string, work *pb.AskForWorkResponse, workErr error) {
+	var missingErr *shuffle.MissingOutputsError
+	var missingMapOutputs []*pb.MapOutput
+	if errors.As(workErr, &missingErr) {
+		missingMapOutputs = missingErr.Missing
+	}
+
+	failed, err := client.ReportFailure(context.Background(), &pb.IFailed{WorkerUuid: workerUuid, WorkFailed: work.FilePath,
+		WorkType: work.WorkType, JobId: work.JobId, Attempt: work.Attempt, ErrorMessage: workErr.Error(),
+		MissingMapOutputs: missingMapOutputs})
+	if err != nil {
+		log.Printf("Error reportando el fallo de %s: %v", work.FilePath, err)
+		return
+	}
+	if !failed.Accepted {
+		log.Printf("El coordinator ignoró el fallo de %s (intento %d): %s", work.FilePath, work.Attempt, failed.Response)
 	}
+}
 
-	return keyValues
+// reconnectBackoff espacia los pedidos de trabajo mientras el coordinator no responde. Con un timeout positivo el
+// worker no termina enseguida porque los reduces pueden seguir necesitando las particiones que sirve, por ejemplo
+// cuando el coordinator se reinicia y recupera su estado del WAL.
+type reconnectBackoff struct {
+	timeout time.Duration
+	delay   time.Duration
+	since   time.Time
 }
 
-func groupByKey(keyValues []mr.KeyValue) map[string][]string {
-	grouped := make(map[string][]string)
+const minReconnectDelay = 100 * time.Millisecond
+const maxReconnectDelay = 5 * time.Second
 
-	for _, kv := range keyValues {
-		grouped[kv.Key] = append(grouped[kv.Key], kv.Value)
+// wait espera antes del próximo intento. Devuelve false si el coordinator ya lleva más de timeout sin responder.
+func (b *reconnectBackoff) wait() bool {
+	if b.timeout <= 0 {
+		return false
+	}
+	if b.since.IsZero() {
+		b.since = time.Now()
+		b.delay = minReconnectDelay
+		log.Printf("El coordinator no responde, reintentando durante %v", b.timeout)
+	}
+	if time.Since(b.since) >= b.timeout {
+		return false
 	}
 
-	return grouped
+	time.Sleep(b.delay)
+	b.delay = min(2*b.delay, maxReconnectDelay)
+	return true
+}
+
+func (b *reconnectBackoff) reset() {
+	if !b.since.IsZero() {
+		log.Printf("El coordinator volvió a responder")
+	}
+	b.since = time.Time{}
 }
 
 func main() {
 
-	if len(os.Args) < 2 {
-		log.Fatal("Uso: go run worker/worker.go <plugin.so>")
+	address := flag.String("addr", transport.DefaultAddress(), "dirección del coordinator: unix:///ruta o host:puerto (también se toma de "+transport.AddressEnv+")")
+	coordinatorTimeout := flag.Duration("coordinator-timeout", 0, "tiempo que el worker sigue esperando (y sirviendo sus particiones) mientras el coordinator no responde, por ejemplo porque se está reiniciando (0 para terminar enseguida)")
+	heartbeatInterval := flag.Duration("heartbeat", 2*time.Second, "intervalo entre heartbeats mientras se ejecuta una tarea")
+	pluginsDir := flag.String("plugins-dir", "plugins", "directorio donde buscar los plugins indicados por el coordinator")
+	shuffleAddr := flag.String("shuffle-addr", "", "dirección en la que el worker sirve a los reduces las particiones de sus maps: unix:///ruta o host:puerto alcanzable por los demás workers (por defecto un socket Unix propio en el directorio temporal)")
+	sortMemoryMB := flag.Int64("sort-memory-mb", 64, "memoria máxima (en MB) para mezclar las particiones de un Reduce; si no alcanza se mezcla por tandas en disco")
+	tlsConfig := transport.RegisterTLSClientFlags(flag.CommandLine)
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		log.Fatal("Uso: go run worker/worker.go [-addr direccion] [-tls-cert cert -tls-key key -tls-ca ca] [-coordinator-timeout 1m] [-heartbeat 2s] [-plugins-dir plugins] [-shuffle-addr direccion] [-sort-memory-mb 64] [plugin.so]")
 	}
 
-	pluginPath := os.Args[1]
-	// Si no incluye la ruta, asumo que está en plugins/
-	if !strings.Contains(pluginPath, "/") {
-		pluginPath = "plugins/" + pluginPath
+	if *heartbeatInterval <= 0 {
+		log.Fatalf("El intervalo entre heartbeats tiene que ser positivo, se indicó %v", *heartbeatInterval)
+	}
+	if *sortMemoryMB < 1 || *sortMemoryMB > maxSortMemoryMB {
+		log.Fatalf("La memoria para mezclar tiene que estar entre 1 y %d MB, se indicó %d", maxSortMemoryMB, *sortMemoryMB)
+	}
+	if *coordinatorTimeout < 0 {
+		log.Fatalf("El tiempo de espera al coordinator no puede ser negativo, se indicó %v", *coordinatorTimeout)
+	}
+
+	// El plugin por línea de comandos solo se usa para los jobs que no indican uno propio
+	defaultPluginPath := ""
+	if flag.NArg() == 1 {
+		defaultPluginPath = resolvePluginPath(*pluginsDir, flag.Arg(0))
 	}
 
 	workerUuid := uuid.New().String()
-	log.Printf("Worker %s iniciando con plugin: %s", workerUuid, pluginPath)
+	log.Printf("Worker %s iniciando con plugin por defecto: %s", workerUuid, defaultPluginPath)
 
-	socketPath := "/tmp/mr-socket.sock"
-	for {
+	dialOption, err := tlsConfig.DialOption()
+	if err != nil {
+		log.Fatalf("Error en la configuración TLS: %v", err)
+	}
 
-		conn, err := grpc.Dial("unix://"+socketPath, grpc.WithInsecure())
+	// La conexión se reutiliza entre tareas: gRPC se reconecta solo, tanto por socket Unix como por TCP
+	conn, err := grpc.Dial(transport.DialTarget(*address), dialOption,
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxAssignmentSize)))
+	if err != nil {
+		log.Printf("Error conectando al coordinator: %v", err)
+		return
+	}
+	defer conn.Close()
 
-		if err != nil {
-			log.Printf("Error conectando al coordinator: %v", err)
-			return
-		}
-		defer conn.Close()
+	client := pb.NewServerClient(conn)
+	jobConfigs := jobconfig.NewCache(client)
 
-		client := pb.NewServerClient(conn)
+	// Las particiones de los maps quedan en el disco de este worker y los reduces se las piden por gRPC
+	shuffleAddress := *shuffleAddr
+	if shuffleAddress == "" {
+		shuffleAddress = "unix://" + filepath.Join(os.TempDir(), "mr-shuffle-"+workerUuid+".sock")
+	}
+	serverOptions, err := tlsConfig.ServerOptions()
+	if err != nil {
+		log.Fatalf("Error en la configuración TLS: %v", err)
+	}
+	stopShuffle, err := shuffle.Serve(shuffleAddress, "intermediate", serverOptions)
+	if err != nil {
+		log.Fatalf("Error iniciando el servidor de particiones: %v", err)
+	}
+	defer stopShuffle()
+	log.Printf("Sirviendo particiones en %s", shuffleAddress)
+
+	// Con los demás workers se usa el mismo certificado, pero su nombre sale de la dirección de cada uno
+	peerTLSConfig := *tlsConfig
+	peerTLSConfig.ServerName = ""
+	peerDialOption, err := peerTLSConfig.DialOption()
+	if err != nil {
+		log.Fatalf("Error en la configuración TLS: %v", err)
+	}
+	fetcher := shuffle.NewFetcher(peerDialOption, mapOutputRetries, mapOutputRetryDelay)
+	defer fetcher.Close()
+
+	reconnect := &reconnectBackoff{timeout: *coordinatorTimeout}
+
+	for {
 
 		resp, err := client.AskForWork(context.Background(), &pb.ImFree{WorkerUuid: workerUuid})
 		if err != nil {
-			if strings.Contains(err.Error(), "connection") &&
-				strings.Contains(err.Error(), "Unavailable") {
-				log.Printf("Worker %s - Coordinator parece cerrado, terminando", workerUuid)
-				return
+			if transport.IsUnavailable(err) {
+				if !reconnect.wait() {
+					log.Printf("Worker %s - Coordinator parece cerrado, terminando", workerUuid)
+					return
+				}
+				continue
 			}
 			log.Printf("Error al solicitar trabajo: %v", err)
 			continue
 		}
+		reconnect.reset()
 
-		mapF, reduceF, err := loadPlugin(pluginPath)
-
-		if err != nil {
-			log.Printf("Error cargando plugin: %v", err)
-			continue
+		// Las respuestas sin job (Wait, Work finished) no necesitan configuración
+		var jobConfig *pb.JobConfig
+		if resp.JobId != "" {
+			jobConfig, err = jobConfigs.Get(resp.JobId, resp.JobGeneration)
+			if err != nil {
+				if transport.IsUnavailable(err) {
+					continue
+				}
+				log.Printf("Error obteniendo la configuración del job %s: %v", resp.JobId, err)
+				reportFailure(client, workerUuid, resp, err)
+				continue
+			}
 		}
 
 		switch resp.WorkType {
+		case "Sample":
+			mrPlug, err := pluginForWork(jobConfig, *pluginsDir, defaultPluginPath)
+			if err != nil {
+				log.Printf("Error cargando plugin: %v", err)
+				reportFailure(client, workerUuid, resp, err)
+				continue
+			}
+			log.Printf("Muestreando %s...", resp.FilePath)
+			stopHeartbeat := startHeartbeat(client, workerUuid, resp, *heartbeatInterval)
+			var sampleKeys []string
+			err = runPluginTask(func() error {
+				sampleKeys, err = executeSampleTask(mrPlug.mapF, resp.InputSplits)
+				return err
+			})
+			stopHeartbeat()
+			if err != nil {
+				log.Printf("Error ejecutando Sample: %v", err)
+				reportFailure(client, workerUuid, resp, err)
+				continue
+			}
+			commitAndReport(client, workerUuid, resp, attempt.NewOutputs(resp.Attempt), sampleKeys, "")
 		case "Map":
+			mrPlug, err := pluginForWork(jobConfig, *pluginsDir, defaultPluginPath)
+			if err != nil {
+				log.Printf("Error cargando plugin: %v", err)
+				reportFailure(client, workerUuid, resp, err)
+				continue
+			}
 			log.Printf("Working...")
+			stopHeartbeat := startHeartbeat(client, workerUuid, resp, *heartbeatInterval)
 			time.Sleep(5 * time.Second)
-			err = executeMapTask(mapF, resp.FilePath, resp.WorkerId, resp.ReducerNumber)
+			outputs := attempt.NewOutputs(resp.Attempt)
+			err = runPluginTask(func() error {
+				return executeMapTask(mrPlug, resp.InputSplits, filepath.Join("intermediate", resp.JobId), resp.TaskId, resp.Attempt, jobConfig.ReducerNumber, jobConfig.TotalOrder, resp.PartitionBounds, intermediateEncoding(jobConfig), outputs)
+			})
+			stopHeartbeat()
 			if err != nil {
+				outputs.Discard()
 				log.Printf("Error ejecutando Map: %v", err)
+				reportFailure(client, workerUuid, resp, err)
 				continue
 			}
-			_, err = client.MarkWorkAsFinished(context.Background(), &pb.IFinished{WorkerUuid: workerUuid, WorkFinished: resp.FilePath, WorkType: "Map"})
+			commitAndReport(client, workerUuid, resp, outputs, nil, shuffleAddress)
+		case "Reduce":
+			mrPlug, err := pluginForWork(jobConfig, *pluginsDir, defaultPluginPath)
 			if err != nil {
-				if strings.Contains(err.Error(), "connection") &&
-					strings.Contains(err.Error(), "Unavailable") {
-					log.Printf("Worker %s - Coordinator parece cerrado, terminando", workerUuid)
-					return
-				}
-				log.Printf("Error marcando Map como terminado: %v", err)
+				log.Printf("Error cargando plugin: %v", err)
+				reportFailure(client, workerUuid, resp, err)
 				continue
 			}
-		case "Reduce":
 			log.Printf("Working...")
+			stopHeartbeat := startHeartbeat(client, workerUuid, resp, *heartbeatInterval)
 			time.Sleep(5 * time.Second)
-			fmt.Printf("DEBUG: reduceTaskId=%d, nMapTasks=%d\n", resp.WorkerId, resp.MapNumber)
-			err = executeReduceTask(reduceF, resp.WorkerId, resp.MapNumber)
+			fmt.Printf("DEBUG: reduceTaskId=%d, nMapTasks=%d\n", resp.TaskId, jobConfig.MapNumber)
+			outputs := attempt.NewOutputs(resp.Attempt)
+			err = runPluginTask(func() error {
+				return executeReduceTask(mrPlug.reduceF, fetcher, resp.JobId, resp.MapOutputs, resp.MapOutputLocations, resp.SkippedMapTaskIds, filepath.Join("intermediate", resp.JobId), jobConfig.OutputPrefix, resp.TaskId, jobConfig.MapNumber, *sortMemoryMB<<20, intermediateEncoding(jobConfig), outputs)
+			})
+			stopHeartbeat()
 			if err != nil {
+				outputs.Discard()
 				log.Printf("Error ejecutando Reduce: %v", err)
+				reportFailure(client, workerUuid, resp, err)
 				continue
 			}
-			_, err = client.MarkWorkAsFinished(context.Background(), &pb.IFinished{WorkerUuid: workerUuid, WorkFinished: resp.FilePath, WorkType: "Reduce"})
-			if err != nil {
-				if strings.Contains(err.Error(), "connection") &&
-					strings.Contains(err.Error(), "Unavailable") {
-					log.Printf("Worker %s - Coordinator parece cerrado, terminando", workerUuid)
-					return
-				}
-				log.Printf("Error marcando Reduce como terminado: %v", err)
-				continue
-			}
+			commitAndReport(client, workerUuid, resp, outputs, nil, "")
+
+		case "Wait":
+			time.Sleep(time.Second)
 
 		case "Work finished":
 			fmt.Println("Trabajo completado")